@@ -0,0 +1,330 @@
+package chessnote
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Command is an embedded command found in a comment, such as
+// "[%eval 0.3]" or "[%clk 0:03:12]".
+type Command struct {
+	// Name is the command name without the leading '%', such as "eval".
+	Name string
+	// Value is the text after the name, with surrounding whitespace removed.
+	Value string
+}
+
+// ParseCommands extracts the embedded commands of the form "[%name value]"
+// from a comment, in source order. Whitespace is allowed around the name
+// and value, including between the '[' and the '%', as in "[ %clk 0:00:30 ]",
+// and the name may also be separated from its value by '=', as in
+// "[%evp=0,15,22]". The value is kept as written, so it may hold commas or
+// nested brackets, as in "[%evp 0,[15,22]]". Several commands may share one
+// pair of brackets, as in "[%clk 0:01:00 %emt 0:00:05]": a '%' at the start
+// of a word begins the next one. Text outside the brackets is ignored, as
+// is an unterminated command at the end of the comment.
+func ParseCommands(comment string) []Command {
+	var commands []Command
+	for {
+		start := strings.IndexByte(comment, '[')
+		if start < 0 {
+			return commands
+		}
+		comment = strings.TrimLeft(comment[start+1:], " \t\n\r")
+		if !strings.HasPrefix(comment, "%") {
+			continue
+		}
+		end := closingBracket(comment)
+		if end < 0 {
+			return commands
+		}
+		body := comment[:end]
+		comment = comment[end+1:]
+
+		// Split the body before every '%' that starts a word outside
+		// nested brackets.
+		depth, from := 0, 0
+		for i := 1; i < len(body); i++ {
+			switch body[i] {
+			case '[':
+				depth++
+			case ']':
+				depth--
+			case '%':
+				if depth == 0 && isCommandSpace(body[i-1]) {
+					commands = appendCommand(commands, body[from+1:i])
+					from = i
+				}
+			}
+		}
+		commands = appendCommand(commands, body[from+1:])
+	}
+}
+
+// closingBracket returns the index in s of the ']' that closes a bracket
+// opened just before s, skipping nested pairs, or -1 if there is none.
+func closingBracket(s string) int {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '[':
+			depth++
+		case ']':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		}
+	}
+	return -1
+}
+
+// appendCommand parses the text of a command after its '%' and appends it
+// to commands, unless its name is empty.
+func appendCommand(commands []Command, text string) []Command {
+	text = strings.TrimSpace(text)
+	name, value := text, ""
+	if i := strings.IndexAny(text, " \t\n\r="); i >= 0 {
+		name, value = text[:i], strings.TrimSpace(text[i:])
+		value = strings.TrimSpace(strings.TrimPrefix(value, "="))
+	}
+	if name == "" {
+		return commands
+	}
+	return append(commands, Command{Name: name, Value: value})
+}
+
+// isCommandSpace reports whether c is whitespace separating the commands
+// that share a pair of brackets.
+func isCommandSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+// Analysis is the engine analysis embedded in the comments of a move, such
+// as {[%eval 0.3] [%depth 24] [%pv e2e4 e7e5]}.
+type Analysis struct {
+	// HasEval reports whether a valid %eval command was found.
+	HasEval bool
+	// Eval is the evaluation from the %eval command in pawns, from White's
+	// point of view. It is 0 for a mate score.
+	Eval float64
+	// Mate is the number of moves to a forced mate from a mate score such as
+	// "#3" or "#-2": positive if White mates and negative if Black mates. It
+	// is 0 if the evaluation is not a mate score.
+	Mate int
+	// Depth is the search depth from the %depth command, or 0 if absent.
+	Depth int
+	// PV is the principal variation from the %pv command, one move per
+	// element exactly as written, in SAN or UCI notation. Move numbers are
+	// dropped.
+	PV []string
+}
+
+// ParseAnalysis extracts the %eval, %depth and %pv commands from a comment.
+// An evaluation is either a number of pawns, such as "0.3" or "-1.25", or a
+// mate score such as "#3" or "#-2", optionally followed by ",depth" as
+// written by some tools. Malformed values are ignored. If a command appears
+// more than once, the last occurrence wins.
+func ParseAnalysis(comment string) Analysis {
+	var a Analysis
+	for _, cmd := range ParseCommands(comment) {
+		switch cmd.Name {
+		case "eval":
+			value := cmd.Value
+			if i := strings.IndexByte(value, ','); i >= 0 {
+				if depth, err := strconv.Atoi(strings.TrimSpace(value[i+1:])); err == nil && depth >= 0 {
+					a.Depth = depth
+				}
+				value = value[:i]
+			}
+			if strings.HasPrefix(value, "#") {
+				if mate, err := strconv.Atoi(value[1:]); err == nil {
+					a.HasEval, a.Eval, a.Mate = true, 0, mate
+				}
+			} else if eval, err := strconv.ParseFloat(value, 64); err == nil {
+				a.HasEval, a.Eval, a.Mate = true, eval, 0
+			}
+		case "depth":
+			if depth, err := strconv.Atoi(cmd.Value); err == nil && depth >= 0 {
+				a.Depth = depth
+			}
+		case "pv":
+			a.PV = nil
+			for _, field := range strings.Fields(cmd.Value) {
+				if isMoveNumber(field) {
+					continue
+				}
+				a.PV = append(a.PV, field)
+			}
+		}
+	}
+	return a
+}
+
+// Analysis returns the engine analysis recorded in the move's comments. When
+// a command appears in several comments, the last occurrence wins.
+func (m Move) Analysis() Analysis {
+	var a Analysis
+	for _, comment := range m.comments() {
+		next := ParseAnalysis(comment)
+		if next.HasEval {
+			a.HasEval, a.Eval, a.Mate = true, next.Eval, next.Mate
+		}
+		if next.Depth != 0 {
+			a.Depth = next.Depth
+		}
+		if next.PV != nil {
+			a.PV = next.PV
+		}
+	}
+	return a
+}
+
+// PVMoves converts the principal variation into moves by playing it out from
+// b, the position the variation starts from, which is the position after
+// the move carrying the analysis. Each element of PV may be in SAN or UCI
+// notation. If an element cannot be interpreted or is illegal, PVMoves
+// returns the moves converted so far together with an error. The board is
+// not modified.
+func (a Analysis) PVMoves(b *Board) ([]Move, error) {
+	pos := *b
+	moves := make([]Move, 0, len(a.PV))
+	for i, s := range a.PV {
+		m, err := pos.pvMove(s)
+		if err != nil {
+			return moves, fmt.Errorf("pv move %d: %w", i+1, err)
+		}
+		if err := pos.Apply(m); err != nil {
+			return moves, fmt.Errorf("pv move %d (%s): %w", i+1, s, err)
+		}
+		moves = append(moves, m)
+	}
+	return moves, nil
+}
+
+// pvMove interprets a principal variation move, trying SAN first and UCI
+// second.
+func (b *Board) pvMove(s string) (Move, error) {
+	if m, ok := (&Parser{}).parseMoveFromRaw(s); ok && b.isLegal(m) {
+		return m, nil
+	}
+	return b.ParseUCI(s)
+}
+
+// isLegal reports whether m can be played in b.
+func (b *Board) isLegal(m Move) bool {
+	next := *b
+	return next.Apply(m) == nil
+}
+
+// ParseUCI converts a move in the UCI long algebraic notation used by chess
+// engines, such as "e2e4", "e7e8q" or "e1g1", into a Move for the side to
+// move in b. The origin is recorded in From with HasFromFile and HasFromRank
+// set, and the piece type, capture and castling fields are taken from the
+// position. Castling is a king moving two or more files along its back rank
+// to the g- or c-file or, as Chess960 engines write it, a king moving onto
+// its own rook. The move's legality is not checked.
+func (b *Board) ParseUCI(s string) (Move, error) {
+	if len(s) != 4 && len(s) != 5 {
+		return Move{}, fmt.Errorf("invalid UCI move %q", s)
+	}
+	from, okFrom := newSquare(s[:2])
+	to, okTo := newSquare(s[2:4])
+	if !okFrom || !okTo {
+		return Move{}, fmt.Errorf("invalid UCI move %q", s)
+	}
+	piece := b.PieceAt(from)
+	if piece.Color != b.turn {
+		return Move{}, fmt.Errorf("invalid UCI move %q: no piece of the side to move on %s", s, squareName(from))
+	}
+
+	m := Move{Piece: piece.Type, From: from, HasFromFile: true, HasFromRank: true, To: to}
+	if len(s) == 5 {
+		promotion, ok := PieceSymbols[unicode.ToUpper(rune(s[4]))]
+		if !ok || piece.Type != Pawn || promotion == Pawn || promotion == King {
+			return Move{}, fmt.Errorf("invalid UCI move %q: invalid promotion", s)
+		}
+		m.Promotion = promotion
+	}
+	if piece.Type == King && from.Rank == backRank(b.turn) && to.Rank == from.Rank {
+		if b.PieceAt(to) == (Piece{Type: Rook, Color: b.turn}) {
+			return Move{Piece: King, IsKingsideCastle: to.File > from.File, IsQueensideCastle: to.File < from.File}, nil
+		}
+		if abs(to.File-from.File) >= 2 && (to.File == 6 || to.File == 2) {
+			return Move{Piece: King, IsKingsideCastle: to.File == 6, IsQueensideCastle: to.File == 2}, nil
+		}
+	}
+	m.IsEnPassant = b.isEnPassant(from, m)
+	m.IsCapture = !b.PieceAt(to).IsEmpty() || m.IsEnPassant
+	return m, nil
+}
+
+// isMoveNumber reports whether s is a move number indication such as "12."
+// or "12...".
+func isMoveNumber(s string) bool {
+	digits := strings.TrimRight(s, ".")
+	if digits == s || digits == "" {
+		return false
+	}
+	_, err := strconv.Atoi(digits)
+	return err == nil
+}
+
+// mateScore is the evaluation, in pawns, given to a forced mate by Score.
+// Mates in fewer moves score slightly higher.
+const mateScore = 1000
+
+// Score returns the evaluation as a single number of pawns from White's
+// point of view, so that evaluations can be compared. A mate score counts
+// as a large finite value: 1000 pawns minus the number of moves to mate,
+// negated if Black mates. It returns 0 if HasEval is false.
+func (a Analysis) Score() float64 {
+	switch {
+	case a.Mate > 0:
+		return float64(mateScore - a.Mate)
+	case a.Mate < 0:
+		return float64(-mateScore - a.Mate)
+	}
+	return a.Eval
+}
+
+// Blunders returns a reference to every move, in the mainline or in a
+// variation, after which the %eval annotation worsened by more than
+// threshold pawns for the side that moved, compared with the evaluation
+// after the previous ply. Mate scores are compared using Analysis.Score, so
+// allowing a forced mate is always a blunder for any reasonable threshold.
+// A move is only considered when both it and the preceding ply carry an
+// evaluation; the first move of a variation is compared with the move
+// before the one it replaces.
+func (g *Game) Blunders(threshold float64) []MoveRef {
+	var refs []MoveRef
+	offset := fenPlyOffset(g.Tags["FEN"])
+	var walk func(moves []Move, prefix []int, firstPly int, prev Analysis)
+	walk = func(moves []Move, prefix []int, firstPly int, prev Analysis) {
+		for i, m := range moves {
+			path := make([]int, len(prefix)+1)
+			copy(path, prefix)
+			path[len(prefix)] = i
+			ply := firstPly + i
+
+			cur := m.Analysis()
+			if prev.HasEval && cur.HasEval {
+				drop := prev.Score() - cur.Score()
+				if (offset+ply)%2 == 0 { // Black moved.
+					drop = -drop
+				}
+				if drop > threshold {
+					refs = append(refs, MoveRef{Path: path, Ply: ply, Move: m})
+				}
+			}
+			for v, variation := range m.Variations {
+				walk(variation, append(path[:len(path):len(path)], v), ply, prev)
+			}
+			prev = cur
+		}
+	}
+	walk(g.Moves, nil, 1, Analysis{})
+	return refs
+}
@@ -0,0 +1,61 @@
+package chessnote
+
+import "strings"
+
+// ToASCII renders the position as an 8x8 text diagram for terminals and
+// test output, seen from White's side: rank 8 at the top and the a-file on
+// the left. White pieces are uppercase letters, Black pieces lowercase, and
+// empty squares dots. Each rank starts with its number, the files are
+// labeled below the board, and a last line names the side to move:
+//
+//	8 r n b q k b n r
+//	7 p p p p p p p p
+//	6 . . . . . . . .
+//	5 . . . . . . . .
+//	4 . . . . . . . .
+//	3 . . . . . . . .
+//	2 P P P P P P P P
+//	1 R N B Q K B N R
+//	  a b c d e f g h
+//	White to move
+func (b *Board) ToASCII() string {
+	return b.ToASCIIFrom(White)
+}
+
+// ToASCIIFrom is like ToASCII, but renders the board as seen by the given
+// side: for Black, rank 1 is at the top and the h-file on the left, so
+// White's pieces are on top.
+func (b *Board) ToASCIIFrom(side Color) string {
+	var sb strings.Builder
+	for row := 0; row < 8; row++ {
+		rank := 7 - row
+		if side == Black {
+			rank = row
+		}
+		sb.WriteByte(byte('1' + rank))
+		for col := 0; col < 8; col++ {
+			file := col
+			if side == Black {
+				file = 7 - col
+			}
+			sb.WriteByte(' ')
+			if p := b.squares[rank][file]; p.IsEmpty() {
+				sb.WriteByte('.')
+			} else {
+				sb.WriteByte(fenSymbol(p))
+			}
+		}
+		sb.WriteByte('\n')
+	}
+	if side == Black {
+		sb.WriteString("  h g f e d c b a\n")
+	} else {
+		sb.WriteString("  a b c d e f g h\n")
+	}
+	if b.turn == Black {
+		sb.WriteString("Black to move\n")
+	} else {
+		sb.WriteString("White to move\n")
+	}
+	return sb.String()
+}
@@ -1,10 +1,14 @@
 package benchmarks
 
 import (
+	"bytes"
+	"io"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/YashBhalodi/chessnote"
+	"github.com/YashBhalodi/chessnote/internal/scanner"
 )
 
 func BenchmarkParseOperaGame(b *testing.B) {
@@ -46,6 +50,7 @@ func BenchmarkParseKasparovGames(b *testing.B) {
 	}
 	games := chessnote.SplitMultiGame(string(pgn))
 
+	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		for _, game := range games {
@@ -56,3 +61,182 @@ func BenchmarkParseKasparovGames(b *testing.B) {
 		}
 	}
 }
+
+func BenchmarkParseKasparovGamesPooled(b *testing.B) {
+	pgn, err := os.ReadFile("../examples/multiple-game-pgn/Kasparov.pgn")
+	if err != nil {
+		b.Fatalf("failed to read PGN file: %v", err)
+	}
+	games := chessnote.SplitMultiGame(string(pgn))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, game := range games {
+			_, err := chessnote.ParseString(game, chessnote.WithPooledMoveBuffers())
+			if err != nil {
+				b.Fatalf("ParseString() failed: %v\nPGN:\n%s", err, game)
+			}
+		}
+	}
+}
+
+func BenchmarkWriteFENKasparovPositions(b *testing.B) {
+	pgn, err := os.ReadFile("../examples/multiple-game-pgn/Kasparov.pgn")
+	if err != nil {
+		b.Fatalf("failed to read PGN file: %v", err)
+	}
+	var positions []*chessnote.Board
+	for _, s := range chessnote.SplitMultiGame(string(pgn)) {
+		game, err := chessnote.ParseString(s)
+		if err != nil {
+			b.Fatalf("ParseString() failed: %v\nPGN:\n%s", err, s)
+		}
+		replayed, err := game.Positions()
+		if err != nil {
+			continue
+		}
+		positions = append(positions, replayed...)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, pos := range positions {
+			if err := pos.WriteFEN(io.Discard); err != nil {
+				b.Fatalf("WriteFEN() failed: %v", err)
+			}
+		}
+	}
+}
+
+func BenchmarkParseKasparovGamesNewParser(b *testing.B) {
+	pgn, err := os.ReadFile("../examples/multiple-game-pgn/Kasparov.pgn")
+	if err != nil {
+		b.Fatalf("failed to read PGN file: %v", err)
+	}
+	games := chessnote.SplitMultiGame(string(pgn))
+	readers := make([]strings.Reader, len(games))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j, game := range games {
+			readers[j].Reset(game)
+			p := chessnote.NewParser(&readers[j])
+			if _, err := p.Parse(); err != nil {
+				b.Fatalf("Parse() failed: %v\nPGN:\n%s", err, game)
+			}
+		}
+	}
+}
+
+func BenchmarkParseKasparovGamesResetParser(b *testing.B) {
+	pgn, err := os.ReadFile("../examples/multiple-game-pgn/Kasparov.pgn")
+	if err != nil {
+		b.Fatalf("failed to read PGN file: %v", err)
+	}
+	games := chessnote.SplitMultiGame(string(pgn))
+	readers := make([]strings.Reader, len(games))
+	p := chessnote.NewParser(strings.NewReader(""))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j, game := range games {
+			readers[j].Reset(game)
+			p.Reset(&readers[j])
+			if _, err := p.Parse(); err != nil {
+				b.Fatalf("Parse() failed: %v\nPGN:\n%s", err, game)
+			}
+		}
+	}
+}
+
+func BenchmarkScanKasparovReader(b *testing.B) {
+	pgn, err := os.ReadFile("../examples/multiple-game-pgn/Kasparov.pgn")
+	if err != nil {
+		b.Fatalf("failed to read PGN file: %v", err)
+	}
+
+	b.SetBytes(int64(len(pgn)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s := scanner.NewScanner(bytes.NewReader(pgn))
+		for tok := s.Scan(); tok.Type != scanner.EOF; tok = s.Scan() {
+		}
+	}
+}
+
+func BenchmarkScanKasparovBytes(b *testing.B) {
+	pgn, err := os.ReadFile("../examples/multiple-game-pgn/Kasparov.pgn")
+	if err != nil {
+		b.Fatalf("failed to read PGN file: %v", err)
+	}
+
+	b.SetBytes(int64(len(pgn)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s := scanner.NewScannerFromBytes(pgn)
+		for tok := s.Scan(); tok.Type != scanner.EOF; tok = s.Scan() {
+		}
+	}
+}
+
+func BenchmarkParseBytesFischerPetrosian(b *testing.B) {
+	pgn, err := os.ReadFile("../examples/advanced_iterator/fischer_petrosian_1959.pgn")
+	if err != nil {
+		b.Fatalf("failed to read PGN file: %v", err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := chessnote.ParseBytes(pgn)
+		if err != nil {
+			b.Fatalf("ParseBytes() failed: %v", err)
+		}
+	}
+}
+
+// annotatedFischerPetrosian returns the Fischer-Petrosian game with a comment
+// after every move, as in a heavily annotated database.
+func annotatedFischerPetrosian(b *testing.B) []byte {
+	pgn, err := os.ReadFile("../examples/advanced_iterator/fischer_petrosian_1959.pgn")
+	if err != nil {
+		b.Fatalf("failed to read PGN file: %v", err)
+	}
+	game, err := chessnote.ParseBytes(pgn)
+	if err != nil {
+		b.Fatalf("ParseBytes() failed: %v", err)
+	}
+	for i := range game.Moves {
+		game.Moves[i].Comments = []string{"A typical idea in this structure, keeping the tension in the centre."}
+	}
+	return []byte(game.ToPGN())
+}
+
+func BenchmarkParseBytesAnnotated(b *testing.B) {
+	pgn := annotatedFischerPetrosian(b)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := chessnote.ParseBytes(pgn)
+		if err != nil {
+			b.Fatalf("ParseBytes() failed: %v", err)
+		}
+	}
+}
+
+func BenchmarkParseBytesAnnotatedLazyComments(b *testing.B) {
+	pgn := annotatedFischerPetrosian(b)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := chessnote.ParseBytes(pgn, chessnote.WithLazyComments())
+		if err != nil {
+			b.Fatalf("ParseBytes() failed: %v", err)
+		}
+	}
+}
@@ -0,0 +1,850 @@
+package chessnote
+
+import "fmt"
+
+// Color identifies the side a piece belongs to, or the side to move.
+type Color int
+
+const (
+	// NoColor is the zero value for Color. It marks an empty square.
+	NoColor Color = iota
+	// White is the side that moves first in a standard game.
+	White
+	// Black is the side that moves second in a standard game.
+	Black
+)
+
+// Opponent returns the opposing color. NoColor is returned unchanged.
+func (c Color) Opponent() Color {
+	switch c {
+	case White:
+		return Black
+	case Black:
+		return White
+	default:
+		return NoColor
+	}
+}
+
+// Piece is a piece on the board, identified by its type and color.
+// The zero value, whose Color is NoColor, represents an empty square.
+type Piece struct {
+	// Type is the kind of piece. It is only meaningful if Color is not NoColor.
+	Type PieceType
+	// Color is the side the piece belongs to.
+	Color Color
+}
+
+// IsEmpty reports whether p represents an empty square.
+func (p Piece) IsEmpty() bool {
+	return p.Color == NoColor
+}
+
+// Castling right flags, packed into a castlingRights bit set.
+const (
+	whiteKingside castlingRights = 1 << iota
+	whiteQueenside
+	blackKingside
+	blackQueenside
+)
+
+// castlingRights is a bit set of the castling moves still available.
+type castlingRights uint8
+
+// Board is a chess position: the placement of the pieces, the side to move,
+// and the state needed to apply further moves. Board values can be copied
+// freely; a copy is fully independent of the original.
+type Board struct {
+	squares        [8][8]Piece // Indexed [rank][file].
+	turn           Color
+	castling       castlingRights
+	epTarget       Square // Only meaningful if hasEPTarget is set.
+	hasEPTarget    bool
+	halfmoveClock  int
+	fullmoveNumber int
+}
+
+// NewBoard returns a board set up in the standard starting position with
+// White to move.
+func NewBoard() *Board {
+	b := &Board{
+		turn:           White,
+		castling:       whiteKingside | whiteQueenside | blackKingside | blackQueenside,
+		fullmoveNumber: 1,
+	}
+	backRank := [8]PieceType{Rook, Knight, Bishop, Queen, King, Bishop, Knight, Rook}
+	for file, piece := range backRank {
+		b.squares[0][file] = Piece{Type: piece, Color: White}
+		b.squares[1][file] = Piece{Type: Pawn, Color: White}
+		b.squares[6][file] = Piece{Type: Pawn, Color: Black}
+		b.squares[7][file] = Piece{Type: piece, Color: Black}
+	}
+	return b
+}
+
+// Grid returns the placement of the pieces as an 8x8 matrix indexed
+// [rank][file], where index [0][0] is a1, [0][7] is h1 and [7][7] is h8.
+// Empty squares hold the zero Piece. The returned array is a copy.
+func (b *Board) Grid() [8][8]Piece {
+	return b.squares
+}
+
+// PieceAt returns the piece on the given square, or the zero Piece if the
+// square is empty or off the board.
+func (b *Board) PieceAt(sq Square) Piece {
+	if !onBoard(sq) {
+		return Piece{}
+	}
+	return b.squares[sq.Rank][sq.File]
+}
+
+// SideToMove returns the color of the side whose turn it is.
+func (b *Board) SideToMove() Color {
+	return b.turn
+}
+
+// HalfmoveClock returns the number of plies since the last capture or pawn
+// move. For a board built from a FEN it continues from the FEN's halfmove
+// field rather than from zero.
+func (b *Board) HalfmoveClock() int {
+	return b.halfmoveClock
+}
+
+// FullmoveNumber returns the number of the current full move. It starts at 1
+// and is incremented after each Black move.
+func (b *Board) FullmoveNumber() int {
+	return b.fullmoveNumber
+}
+
+// IsFiftyMoveDraw reports whether a draw can be claimed under the fifty-move
+// rule, that is whether fifty moves by each side have been played without a
+// capture or pawn move.
+func (b *Board) IsFiftyMoveDraw() bool {
+	return b.halfmoveClock >= 100
+}
+
+// IsCheck reports whether the king of the side to move is attacked. It is
+// computed from the position alone and does not depend on the "+" or "#"
+// annotations of the move that led to it.
+func (b *Board) IsCheck() bool {
+	king, ok := b.kingSquare(b.turn)
+	if !ok {
+		return false
+	}
+	return b.isAttacked(king, b.turn.Opponent())
+}
+
+// IsCheckmate reports whether the side to move is in check and has no legal
+// move.
+func (b *Board) IsCheckmate() bool {
+	return b.IsCheck() && !b.hasLegalMove()
+}
+
+// IsStalemate reports whether the side to move is not in check but has no
+// legal move.
+func (b *Board) IsStalemate() bool {
+	return !b.IsCheck() && !b.hasLegalMove()
+}
+
+// IsInsufficientMaterial reports whether neither side has the material to
+// deliver checkmate: the position holds no pawns, rooks or queens, and
+// either at most one knight or bishop in all, or only bishops that all
+// stand on squares of the same colour.
+func (b *Board) IsInsufficientMaterial() bool {
+	minors := 0
+	bishopColors := [2]bool{}
+	knights := false
+	for rank := 0; rank < 8; rank++ {
+		for file := 0; file < 8; file++ {
+			p := b.squares[rank][file]
+			if p.IsEmpty() {
+				continue
+			}
+			switch p.Type {
+			case Pawn, Rook, Queen:
+				return false
+			case Knight:
+				minors++
+				knights = true
+			case Bishop:
+				minors++
+				bishopColors[(rank+file)%2] = true
+			}
+		}
+	}
+	if minors <= 1 {
+		return true
+	}
+	return !knights && !(bishopColors[0] && bishopColors[1])
+}
+
+// Apply plays the move on the board for the side to move. Because SAN often
+// omits the origin square, Apply resolves it by finding the unique piece of
+// the right type that can legally reach the destination, honoring any
+// disambiguation recorded in m.From. It returns an error, leaving the board
+// unchanged, if no piece or more than one piece can make the move.
+func (b *Board) Apply(m Move) error {
+	if m.IsKingsideCastle || m.IsQueensideCastle {
+		return b.applyCastle(m.IsKingsideCastle)
+	}
+	from, err := b.resolveOrigin(m)
+	if err != nil {
+		return err
+	}
+	b.applyResolved(from, m)
+	return nil
+}
+
+// ResolveFrom returns a copy of the move with its starting square fully
+// specified for the position b, as when building moves from user input
+// against a known position. From holds the square of the unique piece that
+// can legally make the move, HasFromFile and HasFromRank are set, and
+// IsEnPassant is set for an en passant capture. For castling, From and To
+// are the squares the king moves between. Like Apply, it returns an error
+// if no piece or more than one piece can make the move. b is not modified.
+func (m Move) ResolveFrom(b *Board) (Move, error) {
+	if m.IsKingsideCastle || m.IsQueensideCastle {
+		trial := *b
+		if err := trial.applyCastle(m.IsKingsideCastle); err != nil {
+			return Move{}, err
+		}
+		m.From, m.To, _, _, _ = b.castlingSquares(m.IsKingsideCastle)
+	} else {
+		from, err := b.resolveOrigin(m)
+		if err != nil {
+			return Move{}, err
+		}
+		m.From = from
+		m.IsEnPassant = b.isEnPassant(from, m)
+	}
+	m.HasFromFile, m.HasFromRank = true, true
+	return m, nil
+}
+
+// resolveOrigin finds the square the piece described by m moves from.
+func (b *Board) resolveOrigin(m Move) (Square, error) {
+	candidates := b.legalOrigins(m)
+	switch len(candidates) {
+	case 0:
+		if m.HasFromFile || m.HasFromRank || m.From != (Square{}) {
+			bare := m
+			bare.From, bare.HasFromFile, bare.HasFromRank = Square{}, false, false
+			if len(b.legalOrigins(bare)) > 0 {
+				return Square{}, fmt.Errorf("disambiguation %q does not match any %s that can move to %s", disambiguationHint(m), pieceName(m.Piece), squareName(m.To))
+			}
+		}
+		if len(b.origins(m, false)) > 0 {
+			return Square{}, fmt.Errorf("%s to %s would leave the king in check", pieceName(m.Piece), squareName(m.To))
+		}
+		return Square{}, fmt.Errorf("no %s can move to %s", pieceName(m.Piece), squareName(m.To))
+	case 1:
+		return candidates[0], nil
+	}
+
+	return Square{}, fmt.Errorf("ambiguous move: more than one %s can move to %s", pieceName(m.Piece), squareName(m.To))
+}
+
+// disambiguationHint returns the starting file and rank recorded in m, as
+// they would be written in SAN.
+func disambiguationHint(m Move) string {
+	var hint []byte
+	if m.HasFromFile || m.From.File != 0 {
+		hint = append(hint, byte('a'+m.From.File))
+	}
+	if m.HasFromRank || m.From.Rank != 0 {
+		hint = append(hint, byte('1'+m.From.Rank))
+	}
+	return string(hint)
+}
+
+// legalOrigins returns every square holding a piece of the side to move that
+// matches m's piece type and disambiguation, can reach m.To, and would not
+// leave its own king in check by doing so.
+func (b *Board) legalOrigins(m Move) []Square {
+	return b.origins(m, true)
+}
+
+// origins returns the squares described by legalOrigins. If legal is false,
+// moves that would leave the mover's king in check are included.
+func (b *Board) origins(m Move, legal bool) []Square {
+	if !onBoard(m.To) {
+		return nil
+	}
+	var origins []Square
+	for rank := 0; rank < 8; rank++ {
+		for file := 0; file < 8; file++ {
+			from := Square{File: file, Rank: rank}
+			piece := b.squares[rank][file]
+			if piece.Color != b.turn || piece.Type != m.Piece {
+				continue
+			}
+			// Flagged or non-zero components of From are hints.
+			if (m.HasFromFile || m.From.File != 0) && m.From.File != file {
+				continue
+			}
+			if (m.HasFromRank || m.From.Rank != 0) && m.From.Rank != rank {
+				continue
+			}
+			if !b.canReach(from, m.To) {
+				continue
+			}
+			if legal && b.leavesKingInCheck(from, m) {
+				continue
+			}
+			origins = append(origins, from)
+		}
+	}
+	return origins
+}
+
+// hasLegalMove reports whether the side to move has at least one legal move.
+// Castling is not considered: it is only legal when the king could also
+// step to the square it passes over, so it is never the only legal move.
+func (b *Board) hasLegalMove() bool {
+	for rank := 0; rank < 8; rank++ {
+		for file := 0; file < 8; file++ {
+			from := Square{File: file, Rank: rank}
+			if b.squares[rank][file].Color != b.turn {
+				continue
+			}
+			for toRank := 0; toRank < 8; toRank++ {
+				for toFile := 0; toFile < 8; toFile++ {
+					m := Move{To: Square{File: toFile, Rank: toRank}}
+					if b.canReach(from, m.To) && !b.leavesKingInCheck(from, m) {
+						return true
+					}
+				}
+			}
+		}
+	}
+	return false
+}
+
+// canReach reports whether the piece on from can move to to according to its
+// movement rules, ignoring whether the move would expose its own king.
+func (b *Board) canReach(from, to Square) bool {
+	piece := b.PieceAt(from)
+	target := b.PieceAt(to)
+	if piece.IsEmpty() || from == to || target.Color == piece.Color {
+		return false
+	}
+	if piece.Type == Pawn {
+		return b.pawnCanReach(from, to, piece.Color)
+	}
+	return b.attacks(from, to)
+}
+
+// pawnCanReach reports whether a pawn of the given color on from can push
+// or capture to to.
+func (b *Board) pawnCanReach(from, to Square, c Color) bool {
+	dir, startRank := 1, 1
+	if c == Black {
+		dir, startRank = -1, 6
+	}
+	target := b.PieceAt(to)
+	df := to.File - from.File
+	dr := to.Rank - from.Rank
+
+	if df == 0 {
+		if !target.IsEmpty() {
+			return false
+		}
+		if dr == dir {
+			return true
+		}
+		return dr == 2*dir && from.Rank == startRank &&
+			b.PieceAt(Square{File: from.File, Rank: from.Rank + dir}).IsEmpty()
+	}
+	if (df == 1 || df == -1) && dr == dir {
+		return target.Color == c.Opponent() || b.isEnPassantTarget(to, c)
+	}
+	return false
+}
+
+// isEnPassantTarget reports whether a pawn of color c capturing diagonally
+// onto the empty square to would capture en passant.
+func (b *Board) isEnPassantTarget(to Square, c Color) bool {
+	return b.hasEPTarget && c == b.turn && to == b.epTarget
+}
+
+// isEnPassant reports whether m, played from the given origin, is an en
+// passant capture.
+func (b *Board) isEnPassant(from Square, m Move) bool {
+	return b.PieceAt(from).Type == Pawn && from.File != m.To.File && b.isEnPassantTarget(m.To, b.turn)
+}
+
+// attacks reports whether the non-empty piece on from attacks to, taking
+// blocking pieces into account. For pawns only diagonal captures count.
+func (b *Board) attacks(from, to Square) bool {
+	piece := b.PieceAt(from)
+	df := to.File - from.File
+	dr := to.Rank - from.Rank
+	adf, adr := abs(df), abs(dr)
+
+	switch piece.Type {
+	case Pawn:
+		dir := 1
+		if piece.Color == Black {
+			dir = -1
+		}
+		return adf == 1 && dr == dir
+	case Knight:
+		return (adf == 1 && adr == 2) || (adf == 2 && adr == 1)
+	case King:
+		return adf <= 1 && adr <= 1 && (adf+adr) > 0
+	case Bishop:
+		return adf == adr && adf > 0 && b.pathClear(from, to)
+	case Rook:
+		return (df == 0) != (dr == 0) && b.pathClear(from, to)
+	case Queen:
+		return ((adf == adr && adf > 0) || (df == 0) != (dr == 0)) && b.pathClear(from, to)
+	}
+	return false
+}
+
+// pathClear reports whether every square strictly between from and to, which
+// must share a rank, file or diagonal, is empty.
+func (b *Board) pathClear(from, to Square) bool {
+	stepF := sign(to.File - from.File)
+	stepR := sign(to.Rank - from.Rank)
+	sq := Square{File: from.File + stepF, Rank: from.Rank + stepR}
+	for sq != to {
+		if !b.PieceAt(sq).IsEmpty() {
+			return false
+		}
+		sq = Square{File: sq.File + stepF, Rank: sq.Rank + stepR}
+	}
+	return true
+}
+
+// isAttacked reports whether any piece of color by attacks sq.
+func (b *Board) isAttacked(sq Square, by Color) bool {
+	for rank := 0; rank < 8; rank++ {
+		for file := 0; file < 8; file++ {
+			from := Square{File: file, Rank: rank}
+			if b.squares[rank][file].Color == by && from != sq && b.attacks(from, sq) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// kingSquare returns the square of the king of color c.
+func (b *Board) kingSquare(c Color) (Square, bool) {
+	for rank := 0; rank < 8; rank++ {
+		for file := 0; file < 8; file++ {
+			if b.squares[rank][file] == (Piece{Type: King, Color: c}) {
+				return Square{File: file, Rank: rank}, true
+			}
+		}
+	}
+	return Square{}, false
+}
+
+// leavesKingInCheck reports whether playing m from the given origin would
+// leave the mover's king attacked.
+func (b *Board) leavesKingInCheck(from Square, m Move) bool {
+	next := *b
+	next.applyResolved(from, m)
+	king, ok := next.kingSquare(b.turn)
+	if !ok {
+		return false
+	}
+	return next.isAttacked(king, b.turn.Opponent())
+}
+
+// applyResolved plays a non-castling move whose origin is known, updating
+// the side to move, castling rights and move counters.
+func (b *Board) applyResolved(from Square, m Move) {
+	piece := b.squares[from.Rank][from.File]
+	captured := b.squares[m.To.Rank][m.To.File]
+	b.castling &^= b.rightsLostAt(from) | b.rightsLostAt(m.To)
+	if b.isEnPassant(from, m) {
+		// The captured pawn stands beside the origin, behind the target.
+		captured = b.squares[from.Rank][m.To.File]
+		b.squares[from.Rank][m.To.File] = Piece{}
+	}
+
+	b.squares[from.Rank][from.File] = Piece{}
+	if piece.Type == Pawn && m.Promotion != Pawn {
+		piece.Type = m.Promotion
+	}
+	b.squares[m.To.Rank][m.To.File] = piece
+
+	b.hasEPTarget = piece.Type == Pawn && abs(m.To.Rank-from.Rank) == 2
+	if b.hasEPTarget {
+		b.epTarget = Square{File: from.File, Rank: (from.Rank + m.To.Rank) / 2}
+	}
+	if piece.Type == Pawn || !captured.IsEmpty() {
+		b.halfmoveClock = 0
+	} else {
+		b.halfmoveClock++
+	}
+	b.endTurn()
+}
+
+// applyCastle castles the side to move, moving both king and rook. It
+// returns an error, leaving the board unchanged, if the side has lost the
+// right to castle that way, a piece stands in the way of the king or rook,
+// or the king is in check, would pass through or land on an attacked
+// square, or would be left in check.
+//
+// The king and rook are found as described for castlingSquares, so Chess960
+// positions castle correctly even when the king or rook already stands on
+// its destination.
+func (b *Board) applyCastle(kingside bool) error {
+	kingFrom, kingTo, rookFrom, rookTo, ok := b.castlingSquares(kingside)
+	if !ok {
+		return fmt.Errorf("cannot castle: king or rook is not on its original square")
+	}
+	if b.castling&castlingRight(b.turn, kingside) == 0 {
+		return fmt.Errorf("cannot castle: the right to castle has been forfeited")
+	}
+	// Every square the king or rook crosses or lands on must be empty,
+	// apart from the king and rook themselves.
+	first, last := kingFrom.File, kingFrom.File
+	for _, sq := range [...]Square{kingTo, rookFrom, rookTo} {
+		if sq.File < first {
+			first = sq.File
+		}
+		if sq.File > last {
+			last = sq.File
+		}
+	}
+	rank := kingFrom.Rank
+	for file := first; file <= last; file++ {
+		if file != kingFrom.File && file != rookFrom.File && !b.squares[rank][file].IsEmpty() {
+			return fmt.Errorf("cannot castle: %s is occupied", squareName(Square{File: file, Rank: rank}))
+		}
+	}
+	// The king may not castle out of, through, or into check.
+	for file := kingFrom.File; ; file += sign(kingTo.File - kingFrom.File) {
+		sq := Square{File: file, Rank: rank}
+		if b.isAttacked(sq, b.turn.Opponent()) {
+			if sq == kingFrom {
+				return fmt.Errorf("cannot castle out of check")
+			}
+			return fmt.Errorf("cannot castle: %s is attacked", squareName(sq))
+		}
+		if file == kingTo.File {
+			break
+		}
+	}
+
+	next := *b
+	next.squares[kingFrom.Rank][kingFrom.File] = Piece{}
+	next.squares[rookFrom.Rank][rookFrom.File] = Piece{}
+	next.squares[kingTo.Rank][kingTo.File] = Piece{Type: King, Color: b.turn}
+	next.squares[rookTo.Rank][rookTo.File] = Piece{Type: Rook, Color: b.turn}
+	// In Chess960 the rook may have shielded the king's destination from a
+	// piece behind it.
+	if next.isAttacked(kingTo, b.turn.Opponent()) {
+		return fmt.Errorf("cannot castle: %s is attacked", squareName(kingTo))
+	}
+	*b = next
+
+	b.castling &^= castlingRight(b.turn, true) | castlingRight(b.turn, false)
+	b.hasEPTarget = false
+	b.halfmoveClock++
+	b.endTurn()
+	return nil
+}
+
+// castlingSquares returns the squares the king and rook of the side to move
+// travel between when castling on the given side of the board. Following
+// Chess960 (X-FEN), the king is the one on the side's back rank and the rook
+// is the outermost one on that rank beyond the king on the castling side;
+// the king lands on the g- or c-file and the rook on the f- or d-file. In
+// standard chess these are the usual e-file king and corner rooks. It
+// reports false if there is no such king or rook.
+func (b *Board) castlingSquares(kingside bool) (kingFrom, kingTo, rookFrom, rookTo Square, ok bool) {
+	rank := backRank(b.turn)
+	kingFile, ok := b.backRankKing(b.turn)
+	if !ok {
+		return Square{}, Square{}, Square{}, Square{}, false
+	}
+	rookFile, ok := b.castlingRook(b.turn, kingFile, kingside)
+	if !ok {
+		return Square{}, Square{}, Square{}, Square{}, false
+	}
+	kingFrom, rookFrom = Square{File: kingFile, Rank: rank}, Square{File: rookFile, Rank: rank}
+	if kingside {
+		return kingFrom, Square{File: 6, Rank: rank}, rookFrom, Square{File: 5, Rank: rank}, true
+	}
+	return kingFrom, Square{File: 2, Rank: rank}, rookFrom, Square{File: 3, Rank: rank}, true
+}
+
+// backRank returns the rank pieces of color c start on.
+func backRank(c Color) int {
+	if c == Black {
+		return 7
+	}
+	return 0
+}
+
+// backRankKing returns the file of the king of color c if it stands on its
+// back rank.
+func (b *Board) backRankKing(c Color) (int, bool) {
+	rank := backRank(c)
+	for file := 0; file < 8; file++ {
+		if b.squares[rank][file] == (Piece{Type: King, Color: c}) {
+			return file, true
+		}
+	}
+	return 0, false
+}
+
+// castlingRook returns the file of the outermost rook of color c on its back
+// rank beyond kingFile on the given side, the rook that castles that way.
+func (b *Board) castlingRook(c Color, kingFile int, kingside bool) (int, bool) {
+	rank := backRank(c)
+	rook := Piece{Type: Rook, Color: c}
+	if kingside {
+		for file := 7; file > kingFile; file-- {
+			if b.squares[rank][file] == rook {
+				return file, true
+			}
+		}
+		return 0, false
+	}
+	for file := 0; file < kingFile; file++ {
+		if b.squares[rank][file] == rook {
+			return file, true
+		}
+	}
+	return 0, false
+}
+
+// castlingRight returns the castling right flag of color c on the given
+// side of the board.
+func castlingRight(c Color, kingside bool) castlingRights {
+	switch {
+	case c == White && kingside:
+		return whiteKingside
+	case c == White:
+		return whiteQueenside
+	case kingside:
+		return blackKingside
+	default:
+		return blackQueenside
+	}
+}
+
+// endTurn passes the move to the other side.
+func (b *Board) endTurn() {
+	if b.turn == Black {
+		b.fullmoveNumber++
+	}
+	b.turn = b.turn.Opponent()
+}
+
+// rightsLostAt returns the castling rights forfeited when the piece on sq
+// moves or is captured: both of its side's rights for a king on its back
+// rank, and one right for a rook that castles (see castlingSquares).
+func (b *Board) rightsLostAt(sq Square) castlingRights {
+	p := b.PieceAt(sq)
+	if p.IsEmpty() || sq.Rank != backRank(p.Color) {
+		return 0
+	}
+	switch p.Type {
+	case King:
+		return castlingRight(p.Color, true) | castlingRight(p.Color, false)
+	case Rook:
+		kingFile, ok := b.backRankKing(p.Color)
+		if !ok {
+			return 0
+		}
+		kingside := sq.File > kingFile
+		if file, ok := b.castlingRook(p.Color, kingFile, kingside); ok && file == sq.File {
+			return castlingRight(p.Color, kingside)
+		}
+	}
+	return 0
+}
+
+// InitialBoard returns the position the game starts from. This is the
+// position given by the game's FEN tag if it has one, and the standard
+// starting position otherwise.
+func (g *Game) InitialBoard() (*Board, error) {
+	if fen, ok := g.Tags["FEN"]; ok {
+		return ParseFEN(fen)
+	}
+	return NewBoard(), nil
+}
+
+// ReplayError reports the first move of a game that cannot be played
+// during replay, such as a move no piece can make or one that would leave
+// the mover's king in check.
+type ReplayError struct {
+	// Ply is the 1-based index of the move in the game's mainline.
+	Ply int
+	// SAN is the move as written in the game, for example "Nf3".
+	SAN string
+	// FEN is the position the move was played from.
+	FEN string
+	// Reason explains why the move could not be played, for example
+	// "no knight can move to f3".
+	Reason string
+}
+
+// Error returns the ply, the move and the reason it is illegal.
+func (e *ReplayError) Error() string {
+	return fmt.Sprintf("ply %d (%s): %s", e.Ply, e.SAN, e.Reason)
+}
+
+// newReplayError returns a ReplayError for move m, the ply-th move of the
+// game, which failed to apply to b with err.
+func newReplayError(b *Board, ply int, m Move, err error) *ReplayError {
+	return &ReplayError{Ply: ply, SAN: m.String(), FEN: b.FEN(), Reason: err.Error()}
+}
+
+// Positions replays the game's mainline from its initial position and
+// returns the board after every ply. The first element is the position
+// before any move, so the result has len(g.Moves)+1 elements and element i
+// is the position after i plies. If a move cannot be played, the error is a
+// *ReplayError identifying it. The game is not modified; to learn which
+// moves are en passant captures, which SAN does not mark, resolve each move
+// against the position before it with Move.ResolveFrom.
+func (g *Game) Positions() ([]*Board, error) {
+	b, err := g.InitialBoard()
+	if err != nil {
+		return nil, err
+	}
+	positions := make([]*Board, 0, len(g.Moves)+1)
+	start := *b
+	positions = append(positions, &start)
+	for i, m := range g.Moves {
+		if err := b.Apply(m); err != nil {
+			return nil, newReplayError(b, i+1, m, err)
+		}
+		next := *b
+		positions = append(positions, &next)
+	}
+	return positions, nil
+}
+
+// ForEachPosition replays the game's mainline like Positions, but instead of
+// collecting the boards it calls fn with each position as it is reached:
+// first the initial position with ply 0, then the position after every ply,
+// so fn is called len(g.Moves)+1 times for a legal game. A single board is
+// reused and updated in place between calls, so memory use does not grow
+// with the length of the game; fn must not modify b, and must copy it (as
+// in "saved := *b") to keep a position beyond the call. ForEachPosition
+// stops at the first error returned by fn and returns it. If a move cannot
+// be played, the error is a *ReplayError identifying it.
+func (g *Game) ForEachPosition(fn func(ply int, b *Board) error) error {
+	b, err := g.InitialBoard()
+	if err != nil {
+		return err
+	}
+	if err := fn(0, b); err != nil {
+		return err
+	}
+	for i, m := range g.Moves {
+		if err := b.Apply(m); err != nil {
+			return newReplayError(b, i+1, m, err)
+		}
+		if err := fn(i+1, b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckStatus replays the game's mainline and reports, for every move,
+// whether it left the opponent in check. Element i corresponds to
+// g.Moves[i]. Unlike Move.IsCheck, which reflects the PGN annotation, the
+// status is computed from the replayed position.
+func (g *Game) CheckStatus() ([]bool, error) {
+	positions, err := g.Positions()
+	if err != nil {
+		return nil, err
+	}
+	checks := make([]bool, len(g.Moves))
+	for i := range checks {
+		checks[i] = positions[i+1].IsCheck()
+	}
+	return checks, nil
+}
+
+// EndsInCheckmate reports whether the game's mainline ends in checkmate.
+// The verdict is taken from the replayed final position when the mainline
+// can be replayed, so a missing or spurious "#" annotation is corrected.
+// Otherwise it falls back to the IsMate flag of the last mainline move.
+func (g *Game) EndsInCheckmate() bool {
+	if final, ok := g.finalPosition(); ok {
+		return final.IsCheckmate()
+	}
+	return len(g.Moves) > 0 && g.Moves[len(g.Moves)-1].IsMate
+}
+
+// EndsInStalemate reports whether the game's mainline ends in stalemate. PGN
+// has no annotation for stalemate, so it returns false if the mainline
+// cannot be replayed.
+func (g *Game) EndsInStalemate() bool {
+	final, ok := g.finalPosition()
+	return ok && final.IsStalemate()
+}
+
+// finalPosition replays the mainline and returns the position after its
+// last move. It reports false if the mainline cannot be replayed.
+func (g *Game) finalPosition() (*Board, bool) {
+	positions, err := g.Positions()
+	if err != nil {
+		return nil, false
+	}
+	return positions[len(positions)-1], true
+}
+
+func filterOrigins(squares []Square, keep func(Square) bool) []Square {
+	var kept []Square
+	for _, sq := range squares {
+		if keep(sq) {
+			kept = append(kept, sq)
+		}
+	}
+	return kept
+}
+
+func onBoard(sq Square) bool {
+	return sq.File >= 0 && sq.File < 8 && sq.Rank >= 0 && sq.Rank < 8
+}
+
+func squareName(sq Square) string {
+	return string(rune('a'+sq.File)) + string(rune('1'+sq.Rank))
+}
+
+func pieceName(p PieceType) string {
+	switch p {
+	case Knight:
+		return "knight"
+	case Bishop:
+		return "bishop"
+	case Rook:
+		return "rook"
+	case Queen:
+		return "queen"
+	case King:
+		return "king"
+	default:
+		return "pawn"
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func sign(n int) int {
+	switch {
+	case n > 0:
+		return 1
+	case n < 0:
+		return -1
+	}
+	return 0
+}
@@ -0,0 +1,33 @@
+package chessnote
+
+// SquareChange describes a square whose contents differ between two
+// positions.
+type SquareChange struct {
+	// Square is the square that changed.
+	Square Square
+	// Before is the piece on the square in the first position, or the zero
+	// Piece if it was empty.
+	Before Piece
+	// After is the piece on the square in the second position, or the zero
+	// Piece if it is empty.
+	After Piece
+}
+
+// BoardDiff returns every square whose piece differs between before and
+// after, ordered from a1 to h8 rank by rank. Comparing the positions around
+// a single move reports its origin and destination, both king and rook
+// squares for castling, and the square of a pawn captured en passant, which
+// is what a user interface needs to animate the move. Only piece placement
+// is compared; the side to move, castling rights and counters are ignored.
+func BoardDiff(before, after *Board) []SquareChange {
+	var changes []SquareChange
+	for rank := 0; rank < 8; rank++ {
+		for file := 0; file < 8; file++ {
+			b, a := before.squares[rank][file], after.squares[rank][file]
+			if b != a {
+				changes = append(changes, SquareChange{Square: Square{File: file, Rank: rank}, Before: b, After: a})
+			}
+		}
+	}
+	return changes
+}
@@ -0,0 +1,105 @@
+package chessnote
+
+// CheckType classifies the check, if any, given by a move.
+type CheckType int
+
+const (
+	// CheckNone means the move does not give check. It is the zero value
+	// for CheckType.
+	CheckNone CheckType = iota
+	// CheckSingle means the moved piece itself is the only piece giving
+	// check.
+	CheckSingle
+	// CheckDiscovered means the only piece giving check is not the moved
+	// piece: the move uncovered a line from another piece to the king.
+	CheckDiscovered
+	// CheckDouble means two pieces give check at once, which is always
+	// the result of a discovered check by the moved piece and another.
+	CheckDouble
+)
+
+// String returns a lowercase name for the check type, such as "discovered".
+func (c CheckType) String() string {
+	switch c {
+	case CheckSingle:
+		return "single"
+	case CheckDiscovered:
+		return "discovered"
+	case CheckDouble:
+		return "double"
+	default:
+		return "none"
+	}
+}
+
+// CheckType returns the kind of check that playing m in the position b would
+// give. It determines every piece attacking the opposing king after the move
+// and whether the moved piece is among them; for castling, the moved piece
+// is the rook. The board is not modified. It returns an error if m is not
+// legal in b.
+func (b *Board) CheckType(m Move) (CheckType, error) {
+	next := *b
+	if err := next.Apply(m); err != nil {
+		return CheckNone, err
+	}
+	moved := m.To
+	if m.IsKingsideCastle || m.IsQueensideCastle {
+		rank := 0
+		if b.turn == Black {
+			rank = 7
+		}
+		moved = Square{File: 3, Rank: rank}
+		if m.IsKingsideCastle {
+			moved.File = 5
+		}
+	}
+
+	checkers := next.checkers()
+	switch {
+	case len(checkers) == 0:
+		return CheckNone, nil
+	case len(checkers) > 1:
+		return CheckDouble, nil
+	case checkers[0] == moved:
+		return CheckSingle, nil
+	default:
+		return CheckDiscovered, nil
+	}
+}
+
+// checkers returns the squares of every piece attacking the king of the side
+// to move.
+func (b *Board) checkers() []Square {
+	king, ok := b.kingSquare(b.turn)
+	if !ok {
+		return nil
+	}
+	var squares []Square
+	for rank := 0; rank < 8; rank++ {
+		for file := 0; file < 8; file++ {
+			from := Square{File: file, Rank: rank}
+			if b.squares[rank][file].Color == b.turn.Opponent() && b.attacks(from, king) {
+				squares = append(squares, from)
+			}
+		}
+	}
+	return squares
+}
+
+// CheckTypes replays the game's mainline and classifies the check given by
+// every move. Element i corresponds to g.Moves[i]. Like CheckStatus, it is
+// computed from the replayed positions and ignores the "+" and "#"
+// annotations.
+func (g *Game) CheckTypes() ([]CheckType, error) {
+	positions, err := g.Positions()
+	if err != nil {
+		return nil, err
+	}
+	types := make([]CheckType, len(g.Moves))
+	for i, m := range g.Moves {
+		if types[i], err = positions[i].CheckType(m); err != nil {
+			return nil, err
+		}
+	}
+	return types, nil
+}
@@ -3,10 +3,14 @@
 package chessnote
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
+	"unicode"
 
 	"github.com/YashBhalodi/chessnote/internal/scanner"
 	"github.com/YashBhalodi/chessnote/internal/util"
@@ -15,12 +19,27 @@ import (
 // Game represents a single parsed PGN game, including its tag pairs,
 // movetext, and final result.
 type Game struct {
-	// Tags is a map of PGN tag key-value pairs.
+	// Tags is a map of PGN tag key-value pairs. Values are stored exactly as
+	// written, including any multi-byte UTF-8 characters.
 	Tags map[string]string
 	// Moves is a slice of moves made in the game.
 	Moves []Move
+	// Comments holds the comments that appear before the first move of the
+	// game, in source order.
+	Comments []string
 	// Result is the final result of the game (e.g., "1-0", "0-1").
 	Result string
+	// ResultComments holds the comments that appear after the result token,
+	// before the next game begins, in source order.
+	ResultComments []string
+	// ParseWarnings lists recoverable problems noticed while parsing, such as
+	// a move number that does not match the move's position in the game.
+	ParseWarnings []string
+	// Trailing reports whether Parse stopped before the end of its input
+	// because more content followed the game, such as the tag section of a
+	// second game. It usually means the input holds several games and
+	// should be split with SplitMultiGame first.
+	Trailing bool
 }
 
 // Move represents a single move made by one player, capturing all details
@@ -30,6 +49,12 @@ type Move struct {
 	// partially or fully zero, as PGN format often omits this information
 	// when it's not needed for disambiguation.
 	From Square
+	// HasFromFile and HasFromRank report whether the SAN named the file or
+	// the rank of the starting square, as in "Nbd2", "R1a3" or the file of a
+	// pawn capture such as "exd5". They tell an "a"-file or first-rank hint
+	// apart from no hint at all, since both leave the component of From zero.
+	HasFromFile bool
+	HasFromRank bool
 	// To is the destination square of the move. This is always specified.
 	To Square
 	// Piece is the type of piece that was moved.
@@ -43,6 +68,11 @@ type Move struct {
 	IsCheck bool
 	// IsMate indicates whether the move resulted in a checkmate.
 	IsMate bool
+	// IsEnPassant indicates an en passant capture. SAN does not distinguish
+	// en passant from other pawn captures, so the parser never sets it; it
+	// is filled in for a given position by Move.ResolveFrom and
+	// Board.ParseUCI.
+	IsEnPassant bool
 	// IsKingsideCastle indicates a kingside castling move (O-O).
 	IsKingsideCastle bool
 	// IsQueensideCastle indicates a queenside castling move (O-O-O).
@@ -50,9 +80,87 @@ type Move struct {
 	// Variations lists any alternative move sequences that could have been
 	// played. This is used for representing Recursive Annotation Variations (RAVs).
 	Variations [][]Move
+	// Comments holds the comments that follow the move, in source order,
+	// with surrounding whitespace trimmed. Line breaks inside a multi-line
+	// comment are preserved unless WithCommentWhitespaceCollapse is used.
+	// A comment that opens a variation, before its first move, is attached
+	// to the move the variation branches from.
+	Comments []string
 	// NAGs is a slice of Numeric Annotation Glyphs (e.g., $1, $2)
-	// associated with the move.
+	// associated with the move. Inline glyphs written after a move (e.g., "!"
+	// or "??") are converted to their NAG equivalents and stored here in
+	// source order alongside explicit NAGs, so "Qh5?? $10" yields [4, 10].
 	NAGs []int
+	// Timestamp is the time the move was made, as recorded by correspondence
+	// servers in a comment following the move: either a "[%ts ...]" command
+	// or a comment holding nothing but a date, such as {2023.05.14} or
+	// {2023-05-14 18:30}. The first recognized timestamp wins, and the
+	// comment itself is kept unchanged. It is the zero time if the move has
+	// no timestamp.
+	Timestamp time.Time
+
+	// commentSrc and commentSpans hold the comments left in the source by
+	// WithLazyComments, as byte ranges of commentSrc.
+	commentSrc   []byte
+	commentSpans []commentSpan
+}
+
+// commentSpan is the byte range of a comment's text in a parser's input.
+type commentSpan struct {
+	start, end int
+}
+
+// Comment returns the move's comments joined by a space, or "" if it has
+// none. Unlike Comments, it also covers comments left in the source by
+// WithLazyComments, copying their text out only when called.
+func (m Move) Comment() string {
+	return strings.Join(m.comments(), " ")
+}
+
+// comments returns the move's comments, followed by those left in the
+// source by WithLazyComments. It returns Comments itself when there are no
+// lazy comments, so callers must not modify the result.
+func (m Move) comments() []string {
+	if len(m.commentSpans) == 0 {
+		return m.Comments
+	}
+	comments := make([]string, 0, len(m.Comments)+len(m.commentSpans))
+	comments = append(comments, m.Comments...)
+	for _, span := range m.commentSpans {
+		comments = append(comments, strings.TrimSpace(string(m.commentSrc[span.start:span.end])))
+	}
+	return comments
+}
+
+// PrimaryAnnotation returns the move-quality NAG (1-6, i.e. !, ?, !!, ??,
+// !?, ?!) attached to the move, or 0 if there is none. If several are
+// present, the first one in source order wins.
+func (m Move) PrimaryAnnotation() int {
+	for _, nag := range m.NAGs {
+		if nag >= 1 && nag <= 6 {
+			return nag
+		}
+	}
+	return 0
+}
+
+// IsPawnMove reports whether the move was made by a pawn. Because Pawn is the
+// zero value of PieceType, a Move whose Piece was never set also counts as a
+// pawn move.
+func (m Move) IsPawnMove() bool {
+	return m.Piece == Pawn
+}
+
+// IsPromotion reports whether the move promotes a pawn. Promotion holds the
+// zero value Pawn, not a separate "none" value, when there is no promotion,
+// so this is the same as m.Promotion != Pawn.
+func (m Move) IsPromotion() bool {
+	return m.Promotion != Pawn
+}
+
+// IsCastle reports whether the move is kingside or queenside castling.
+func (m Move) IsCastle() bool {
+	return m.IsKingsideCastle || m.IsQueensideCastle
 }
 
 // Square represents a single square on the board (e.g., e4).
@@ -91,6 +199,29 @@ var PieceSymbols = map[rune]PieceType{
 	'K': King,
 }
 
+// glyphNAGs maps the inline move-quality glyphs to their NAG equivalents,
+// as defined by the PGN standard.
+var glyphNAGs = map[string]int{
+	"!":  1,
+	"?":  2,
+	"!!": 3,
+	"??": 4,
+	"!?": 5,
+	"?!": 6,
+}
+
+// NAGSymbol returns the inline glyph for a move-quality NAG, such as "!"
+// for $1 or "?!" for $6, and the empty string for any other NAG. This
+// includes the null annotation $0, which is a valid NAG with no symbol.
+func NAGSymbol(nag int) string {
+	for glyph, n := range glyphNAGs {
+		if n == nag {
+			return glyph
+		}
+	}
+	return ""
+}
+
 // ParserConfig holds configuration settings for the parser.
 type ParserConfig struct {
 	// Strict mode requires that a PGN game must end with a valid result token
@@ -98,36 +229,263 @@ type ParserConfig struct {
 	// at the end of the file without a result token.
 	// It is enabled by default.
 	Strict bool
+	// RequireDecisiveOrDraw rejects games whose movetext ends with the "*"
+	// (in progress) result token. See WithRequireDecisiveOrDraw.
+	RequireDecisiveOrDraw bool
+	// LenientTags accepts tag values that are single-quoted or not quoted
+	// at all, in addition to the standard double-quoted strings.
+	LenientTags bool
+	// CaseInsensitivePieces also accepts lowercase piece letters in moves.
+	// See WithCaseInsensitivePieces.
+	CaseInsensitivePieces bool
+	// TrimTagValues removes surrounding whitespace and a leading byte order
+	// mark from tag values. See WithTrimTagValues.
+	TrimTagValues bool
+	// SkipComments discards all comments instead of attaching them to the
+	// game's moves.
+	SkipComments bool
+	// CollapseCommentWhitespace replaces every run of whitespace inside a
+	// comment, including line breaks, with a single space.
+	CollapseCommentWhitespace bool
+	// CommentHandler, if set, is called with each comment as it is parsed
+	// instead of attaching the comment to the game. See WithCommentHandler.
+	CommentHandler func(move *Move, comment string)
+	// LazyComments leaves the comments of moves in the source buffer of
+	// ParseBytes instead of copying them. See WithLazyComments.
+	LazyComments bool
+	// PooledMoveBuffers parses each line of moves into a reusable buffer and
+	// copies it into an exactly-sized slice. See WithPooledMoveBuffers.
+	PooledMoveBuffers bool
+	// NormalizeResult, if not ResultSourceNone, reconciles the movetext
+	// result token and the Result tag of every parsed game by copying the
+	// preferred one over the other. See Game.NormalizeResult.
+	NormalizeResult ResultSource
+	// TokenErrorHandler, if set, decides whether an unexpected token is
+	// skipped or aborts parsing. See WithTokenErrorHandler.
+	TokenErrorHandler func(tok Token) bool
+}
+
+// Token is a lexical token of PGN input, as returned by a Tokenizer or
+// passed to a TokenErrorHandler for a token the parser could not make sense
+// of.
+type Token struct {
+	// Kind is the kind of the token.
+	Kind TokenKind
+	// Literal is the text of the token, such as "@" or "]". Tag values and
+	// comments are given without their quotes or braces, and a tag value's
+	// escaped characters are unescaped.
+	Literal string
+	// Line and Column give the position of the token's first character.
+	// Both are 1-based, and columns count runes, not bytes.
+	Line   int
+	Column int
+}
+
+// DefaultParserConfig returns the configuration used by NewParser before any
+// options are applied. It is a convenient starting point for WithConfig.
+func DefaultParserConfig() ParserConfig {
+	return ParserConfig{
+		Strict: true,
+	}
 }
 
 // A ParserOption configures a Parser.
 type ParserOption func(*ParserConfig)
 
+// WithConfig returns a ParserOption that replaces the parser's entire
+// configuration with cfg. It lets advanced users build a fully-specified
+// configuration in one place. Options listed after WithConfig are applied on
+// top of cfg, and any listed before it are discarded. Start from
+// DefaultParserConfig to keep the default settings for fields you do not set.
+func WithConfig(cfg ParserConfig) ParserOption {
+	return func(c *ParserConfig) {
+		*c = cfg
+	}
+}
+
 // WithLaxParsing returns a ParserOption that disables strict parsing mode.
 // In lax mode, the parser will not require a final game result token and will
-// successfully parse a game that ends abruptly at the end of the file.
+// successfully parse a game that ends abruptly at the end of the file. It
+// also accepts a move split across two tokens, such as "e 4", when the two
+// together form a valid move.
 func WithLaxParsing() ParserOption {
 	return func(c *ParserConfig) {
 		c.Strict = false
 	}
 }
 
+// WithRequireDecisiveOrDraw returns a ParserOption that rejects games still
+// in progress, for archives that should only hold finished games. A game
+// whose movetext ends with "*" fails to parse; 1-0, 0-1 and 1/2-1/2 are
+// accepted. The option only concerns the result token that is present:
+// whether a game may omit its result entirely is still decided by strict or
+// lax mode.
+func WithRequireDecisiveOrDraw() ParserOption {
+	return func(c *ParserConfig) {
+		c.RequireDecisiveOrDraw = true
+	}
+}
+
+// WithLenientTags returns a ParserOption that recovers tag values written by
+// non-compliant exporters, such as [Event 'Foo'] or [Round 3]. Single-quoted
+// values and bare values, read up to the closing ']', are stored as strings.
+// Without this option the parser requires the double-quoted values mandated
+// by the PGN standard.
+func WithLenientTags() ParserOption {
+	return func(c *ParserConfig) {
+		c.LenientTags = true
+	}
+}
+
+// WithCaseInsensitivePieces returns a ParserOption that recovers moves from
+// sloppy PGN files that write piece letters in lowercase, such as "nf3" or
+// "qxd5". It also accepts a lowercase promotion piece, as in "e8=q".
+//
+// A lowercase "b" is ambiguous with the b-file, so a move is first read as
+// standard SAN and only reread with an uppercase piece letter if that fails:
+// "bxc4" and "b4" remain pawn moves, while "bc4", which is not a valid pawn
+// move, is read as Bc4. Without this option, lowercase piece letters are
+// rejected.
+func WithCaseInsensitivePieces() ParserOption {
+	return func(c *ParserConfig) {
+		c.CaseInsensitivePieces = true
+	}
+}
+
+// WithTrimTagValues returns a ParserOption that cleans up tag values written
+// by sloppy exporters: surrounding whitespace is removed, as is a UTF-8 byte
+// order mark at the start of a value, so [Event " Padded Name "] is stored
+// as "Padded Name". This makes filtering by event or player names reliable.
+// Without this option tag values are stored byte for byte.
+func WithTrimTagValues() ParserOption {
+	return func(c *ParserConfig) {
+		c.TrimTagValues = true
+	}
+}
+
+// WithSkipComments returns a ParserOption that discards comments instead of
+// attaching them to moves. This saves memory when annotations are not needed.
+// It overrides any earlier WithCommentHandler option.
+func WithSkipComments() ParserOption {
+	return func(c *ParserConfig) {
+		c.SkipComments = true
+		c.CommentHandler = nil
+	}
+}
+
+// WithCommentWhitespaceCollapse returns a ParserOption that normalizes every
+// run of whitespace inside a comment, including line breaks, to a single
+// space. This is useful when displaying comments that were wrapped across
+// several lines in the source. By default, comment text is kept verbatim
+// apart from trimming surrounding whitespace.
+func WithCommentWhitespaceCollapse() ParserOption {
+	return func(c *ParserConfig) {
+		c.CollapseCommentWhitespace = true
+	}
+}
+
+// WithCommentHandler returns a ParserOption that streams comments to fn as
+// they are parsed rather than retaining them on the game, so callers can
+// process enormous annotated databases without holding every comment in
+// memory. fn receives the move the comment follows, or nil for a comment
+// before the first move of the game or after its result. The move pointer is
+// only valid for the duration of the call. It overrides any earlier
+// WithSkipComments option.
+func WithCommentHandler(fn func(move *Move, comment string)) ParserOption {
+	return func(c *ParserConfig) {
+		c.CommentHandler = fn
+		c.SkipComments = false
+	}
+}
+
+// WithLazyComments returns a ParserOption that saves an allocation per
+// comment when parsing large annotated files whose comments are rarely read.
+// Instead of copying a move's comments into Comments, ParseBytes records
+// where they lie in its input, which the game then keeps alive, and
+// Move.Comment slices them out on demand. Comments before the first move or
+// after the result are still copied. The package's own functions, such as
+// ToPGN, Analysis and DumpTree, see lazy comments, but code that reads
+// Comments directly does not.
+//
+// Since lazy comments are never read during parsing, the option bypasses
+// the processing a comment otherwise receives: WithCommentWhitespaceCollapse
+// is not applied to them, only their surrounding whitespace is trimmed, and
+// no timestamp is extracted from them into Timestamp. The option has no
+// effect on parsers reading from an io.Reader, including ParseString, and
+// WithSkipComments and WithCommentHandler take precedence over it.
+func WithLazyComments() ParserOption {
+	return func(c *ParserConfig) {
+		c.LazyComments = true
+	}
+}
+
+// WithPooledMoveBuffers returns a ParserOption that reduces allocations when
+// importing many games. Instead of growing each game's move slices with
+// append, the parser collects the moves of every line in a buffer drawn from
+// a shared pool and then copies them into a slice of exactly the right
+// length. The buffers never escape the parser: the returned Game owns all of
+// its slices, and they are not reused by later parses.
+func WithPooledMoveBuffers() ParserOption {
+	return func(c *ParserConfig) {
+		c.PooledMoveBuffers = true
+	}
+}
+
+// WithResultNormalization returns a ParserOption that cleans up games whose
+// Result tag disagrees with the result token ending their movetext, a common
+// form of database corruption. The source named by prefer wins and the other
+// is overwritten, as done by Game.NormalizeResult. Without this option both
+// values are kept as written; Game.ResultConsistent reports whether they
+// agree.
+func WithResultNormalization(prefer ResultSource) ParserOption {
+	return func(c *ParserConfig) {
+		c.NormalizeResult = prefer
+	}
+}
+
+// WithTokenErrorHandler returns a ParserOption that lets a forgiving
+// importer recover from stray characters and other unexpected tokens in
+// the movetext or before a game. Instead of failing, the parser calls fn
+// with the offending token: if fn returns true, the token is skipped, a
+// warning naming it is added to the game's ParseWarnings and parsing
+// continues; if it returns false, parsing fails as it would without the
+// option. Invalid moves are not passed to fn.
+func WithTokenErrorHandler(fn func(tok Token) bool) ParserOption {
+	return func(c *ParserConfig) {
+		c.TokenErrorHandler = fn
+	}
+}
+
 // Parser is a PGN parser that reads from an io.Reader and parses it into a Game.
-// It implements a standard recursive descent parser.
+// It implements a standard recursive descent parser. A Parser is not safe for
+// concurrent use by multiple goroutines.
 type Parser struct {
-	s      *scanner.Scanner
-	tok    scanner.Token // The current token
-	config ParserConfig
+	s         *scanner.Scanner
+	tok       scanner.Token // The current token
+	config    ParserConfig
+	game      *Game  // The game currently being parsed
+	plyOffset int    // Plies played before the game's initial position
+	src       []byte // The input comments are left in; see WithLazyComments.
+	// sharedTags, if set, are copied into every game before its own tags
+	// are parsed. See ParseWithSharedTags.
+	sharedTags map[string]string
+	// streaming makes a game return as soon as its result is read, leaving
+	// the result as the current token and the game in unfinished until
+	// the caller calls finishResult. See GameReader.Next.
+	streaming  bool
+	unfinished *Game
 }
 
 // NewParser creates and returns a new PGN Parser for the given reader.
 // By default, it operates in strict mode. Behavior can be customized with
 // ParserOptions, such as WithLaxParsing().
 func NewParser(r io.Reader, opts ...ParserOption) *Parser {
-	// Default configuration
-	config := ParserConfig{
-		Strict: true,
-	}
+	return newParser(scanner.NewScanner(r), opts...)
+}
+
+// newParser returns a Parser that reads tokens from s.
+func newParser(s *scanner.Scanner, opts ...ParserOption) *Parser {
+	config := DefaultParserConfig()
 
 	// Apply all options
 	for _, opt := range opts {
@@ -135,25 +493,87 @@ func NewParser(r io.Reader, opts ...ParserOption) *Parser {
 	}
 
 	p := &Parser{
-		s:      scanner.NewScanner(r),
+		s:      s,
 		config: config,
 	}
+	if config.LazyComments {
+		p.src = s.Source()
+		s.SetLazyComments(true)
+	}
 	p.scan() // Initialize the first token
 	return p
 }
 
+// Reset makes the parser read from r, discarding any unread input from its
+// previous reader, and scans the first token of the new input. The parser
+// keeps its configuration and reuses its read buffer, so a server parsing
+// many small PGN documents can keep one Parser per goroutine instead of
+// allocating a new one for every document.
+func (p *Parser) Reset(r io.Reader) {
+	p.s.Reset(r)
+	p.src = nil
+	p.game = nil
+	p.plyOffset = 0
+	p.sharedTags = nil
+	p.unfinished = nil
+	p.scan()
+}
+
+// Config returns the effective configuration of the parser, after all of its
+// options have been applied.
+func (p *Parser) Config() ParserConfig {
+	return p.config
+}
+
 // scan moves to the next token and sets it as the parser's current token.
 func (p *Parser) scan() {
 	p.tok = p.s.Scan()
 }
 
+// errorf returns an error prefixed with the position of the current token.
+func (p *Parser) errorf(format string, args ...interface{}) error {
+	pos := p.s.Pos()
+	return fmt.Errorf("line %d, column %d: %s", pos.Line, pos.Column, fmt.Sprintf(format, args...))
+}
+
 // Parse reads and parses the entire PGN data from the reader, returning a
 // single Game object. It expects the PGN data to contain exactly one game.
 // The parser stops at the first game-terminating symbol (*, 1-0, etc.).
+//
+// If more content follows the game, the returned game's Trailing field is
+// set and the parser is left at the start of that content, so calling Parse
+// again returns the next game. Together with More, this reads every game of
+// a concatenated PGN:
+//
+//	for p.More() {
+//		game, err := p.Parse()
+//		...
+//	}
 func (p *Parser) Parse() (*Game, error) {
+	game, err := p.parseGame()
+	if err != nil {
+		return nil, err
+	}
+	game.NormalizeResult(p.config.NormalizeResult)
+	return game, nil
+}
+
+// More reports whether any input is left for Parse to read, that is,
+// whether the parser has not yet reached the end of its input. It is true
+// for a new parser unless its input is empty.
+func (p *Parser) More() bool {
+	return p.tok.Type != scanner.EOF
+}
+
+// parseGame parses a single game, as described by Parse.
+func (p *Parser) parseGame() (*Game, error) {
 	game := &Game{
-		Tags: make(map[string]string),
+		Tags: make(map[string]string, len(p.sharedTags)),
+	}
+	for key, value := range p.sharedTags {
+		game.Tags[key] = value
 	}
+	p.game = game
 
 	for {
 		switch p.tok.Type {
@@ -166,34 +586,72 @@ func (p *Parser) Parse() (*Game, error) {
 			return game, nil
 		case scanner.LBRACKET:
 			// If we are already parsing moves and see a new tag, the game has ended
-			// without a result marker.
+			// without a result marker and another game follows it.
 			if len(game.Moves) > 0 {
+				game.Trailing = true
 				return game, nil
 			}
 			if err := p.parseTagPair(game); err != nil {
 				return nil, err
 			}
 		case scanner.COMMENT:
-			p.scan() // Ignore comments
+			p.addComment(nil, &game.Comments)
 		case scanner.IDENT, scanner.NUMBER:
 			// Once we see an ident or number outside a tag, we are in the movetext.
-			if err := p.parseMovetext(&game.Moves); err != nil {
+			p.plyOffset = fenPlyOffset(game.Tags["FEN"])
+			if err := p.parseLine(&game.Moves, nil, &game.Comments, 0); err != nil {
 				return nil, err
 			}
 			// After parsing movetext, we might have a result token.
 			if isResult(p.tok) {
+				if p.config.RequireDecisiveOrDraw && p.tok.Literal == "*" {
+					return nil, fmt.Errorf("game must be finished, got result %q", p.tok.Literal)
+				}
 				game.Result = p.tok.Literal
+				if game.Result == "½-½" {
+					// The scanner also accepts the Unicode draw.
+					game.Result = OutcomeDraw.String()
+				}
+				if p.streaming {
+					// Reading past the result would wait for input that
+					// may not have arrived yet; see finishResult.
+					p.unfinished = game
+					return game, nil
+				}
+				p.finishResult(game)
 			} else if p.config.Strict {
 				// If we finish parsing moves and don't have a result, it's an error in strict mode.
 				return nil, fmt.Errorf("game must end with a result token, got %v", p.tok)
 			}
+			// Anything left after the game means the input was not a single game.
+			game.Trailing = p.tok.Type != scanner.EOF
 			return game, nil
+		case scanner.RPAREN:
+			if !p.skipToken() {
+				return nil, p.errorf("unexpected ')' before the game's movetext: no variation is open")
+			}
+		case scanner.RBRACKET:
+			if !p.skipToken() {
+				return nil, p.errorf("unexpected ']' outside a tag pair")
+			}
 		default:
-			return nil, fmt.Errorf("unexpected token at start of game: %v", p.tok)
+			if !p.skipToken() {
+				return nil, p.errorf("unexpected token at start of game: %v", p.tok)
+			}
 		}
 	}
 }
 
+// finishResult consumes the result token of game and the comments that
+// follow it. Comments between the result and the next game belong to this
+// game, not to the next one.
+func (p *Parser) finishResult(game *Game) {
+	p.scan() // Consume the result
+	for p.tok.Type == scanner.COMMENT {
+		p.addComment(nil, &game.ResultComments)
+	}
+}
+
 func (p *Parser) parseTagPair(g *Game) error {
 	p.scan() // Consume '['
 	key := p.tok
@@ -201,11 +659,19 @@ func (p *Parser) parseTagPair(g *Game) error {
 		return fmt.Errorf("expected ident for tag key, got %v", key)
 	}
 
-	p.scan() // Consume key
+	// Consume key
+	if p.config.LenientTags {
+		p.tok = p.s.ScanTagValue()
+	} else {
+		p.scan()
+	}
 	value := p.tok
 	if value.Type != scanner.STRING {
 		return fmt.Errorf("expected string for tag value, got %v", value)
 	}
+	if p.config.TrimTagValues {
+		value.Literal = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(value.Literal), "\uFEFF"))
+	}
 	g.Tags[key.Literal] = value.Literal
 
 	p.scan() // Consume value
@@ -216,7 +682,49 @@ func (p *Parser) parseTagPair(g *Game) error {
 	return nil
 }
 
-func (p *Parser) parseMovetext(moves *[]Move) error {
+// moveBufferPool holds the scratch buffers used by WithPooledMoveBuffers.
+var moveBufferPool = sync.Pool{
+	New: func() interface{} {
+		buf := make([]Move, 0, 128)
+		return &buf
+	},
+}
+
+// maxPooledMoves bounds the capacity of buffers returned to the pool, so a
+// single unusually long game does not pin a large buffer in memory.
+const maxPooledMoves = 1024
+
+// parseLine parses a line of moves like parseMovetext. With pooled move
+// buffers enabled, it parses into a pooled buffer and stores an exactly-sized
+// copy in moves, which must be empty.
+func (p *Parser) parseLine(moves *[]Move, parent *Move, leading *[]string, firstPly int) error {
+	if !p.config.PooledMoveBuffers {
+		return p.parseMovetext(moves, parent, leading, firstPly)
+	}
+	buf := moveBufferPool.Get().(*[]Move)
+	err := p.parseMovetext(buf, parent, leading, firstPly)
+	if err == nil && len(*buf) > 0 {
+		*moves = make([]Move, len(*buf))
+		copy(*moves, *buf)
+	}
+	// Drop the buffer's references to the moves' slices before reuse.
+	for i := range *buf {
+		(*buf)[i] = Move{}
+	}
+	*buf = (*buf)[:0]
+	if cap(*buf) <= maxPooledMoves {
+		moveBufferPool.Put(buf)
+	}
+	return err
+}
+
+// parseMovetext parses a line of moves into moves. Comments that appear
+// before the first move of the line are attached to leading, on behalf of
+// parent, which is nil for the mainline. firstPly is the index, counted in
+// plies from the start of the game, of the line's first move.
+func (p *Parser) parseMovetext(moves *[]Move, parent *Move, leading *[]string, firstPly int) error {
+	number := 0 // The move number written before the next move, if any.
+	dots := 0   // The number of dots written after that move number.
 	for {
 		switch p.tok.Type {
 		case scanner.EOF, scanner.ASTERISK, scanner.RPAREN, scanner.LBRACKET:
@@ -225,6 +733,24 @@ func (p *Parser) parseMovetext(moves *[]Move) error {
 			if isResult(p.tok) {
 				return nil // Let caller handle result
 			}
+			ply := firstPly + len(*moves)
+			// A number followed by an ellipsis, as in "12...", continues
+			// with a Black move. Movetext that starts with one, such as a
+			// fragment copied from the middle of a game, therefore starts
+			// with Black to move at that move number, unless a FEN tag
+			// says otherwise.
+			if number != 0 && dots >= 3 && ply == 0 && parent == nil {
+				if _, ok := p.game.Tags["FEN"]; !ok {
+					p.plyOffset = 2*(number-1) + 1
+				}
+			}
+			if want := (p.plyOffset+ply)/2 + 1; number != 0 && number != want {
+				p.warnf("ply %d: move number %d does not match the expected move number %d", ply+1, number, want)
+			}
+			if number != 0 && dots >= 3 && (p.plyOffset+ply)%2 == 0 {
+				p.warnf("ply %d: move number %d... indicates a Black move, but it is White's turn", ply+1, number)
+			}
+			number, dots = 0, 0
 			move, err := p.parseMove()
 			if err != nil {
 				return err
@@ -245,26 +771,80 @@ func (p *Parser) parseMovetext(moves *[]Move) error {
 			}
 			lastMove.NAGs = append(lastMove.NAGs, nag)
 			p.scan()
-		case scanner.NUMBER, scanner.DOT, scanner.COMMENT:
-			p.scan() // Ignore
+		case scanner.GLYPH:
+			if len(*moves) == 0 {
+				return fmt.Errorf("found annotation glyph before any moves")
+			}
+			lastMove := &(*moves)[len(*moves)-1]
+			nag, ok := glyphNAGs[p.tok.Literal]
+			if !ok {
+				return fmt.Errorf("invalid annotation glyph: %v", p.tok.Literal)
+			}
+			lastMove.NAGs = append(lastMove.NAGs, nag)
+			p.scan()
+		case scanner.COMMENT:
+			if len(*moves) == 0 {
+				p.addComment(parent, leading)
+			} else {
+				lastMove := &(*moves)[len(*moves)-1]
+				if ts, ok := commentTimestamp(p.tok.Literal); ok && lastMove.Timestamp.IsZero() {
+					lastMove.Timestamp = ts
+				}
+				p.addComment(lastMove, &lastMove.Comments)
+			}
+		case scanner.NUMBER:
+			number = moveNumber(p.tok.Literal)
+			dots = 0
+			p.scan()
+		case scanner.DOT:
+			if p.tok.Literal == "…" {
+				dots += 3
+			} else {
+				dots++
+			}
+			p.scan()
 		case scanner.LPAREN:
 			if len(*moves) == 0 {
 				return fmt.Errorf("found variation before any moves")
 			}
 			lastMove := &(*moves)[len(*moves)-1]
-			if err := p.parseRAV(lastMove); err != nil {
+			if err := p.parseRAV(lastMove, firstPly+len(*moves)-1); err != nil {
 				return err
 			}
 		default:
-			return fmt.Errorf("unexpected token in movetext: %v", p.tok)
+			if !p.skipToken() {
+				return fmt.Errorf("unexpected token in movetext: %v", p.tok)
+			}
 		}
 	}
 }
 
-func (p *Parser) parseRAV(parentMove *Move) error {
+// maxMoveNumber is the largest move number checked against a move's
+// position in the game, far beyond the length of any real game.
+const maxMoveNumber = 1 << 20
+
+// moveNumber returns the value of the move number lit, which holds only
+// digits, or 0, meaning the number is not checked, if it exceeds
+// maxMoveNumber. It stops reading at that point, so an enormous run of
+// digits costs no more than a short one.
+func moveNumber(lit string) int {
+	n := 0
+	for i := 0; i < len(lit); i++ {
+		n = n*10 + int(lit[i]-'0')
+		if n > maxMoveNumber {
+			return 0
+		}
+	}
+	return n
+}
+
+// parseRAV parses a variation that replaces parentMove, which is played at
+// the given ply index. Comments before the variation's first move are
+// attached to parentMove.
+func (p *Parser) parseRAV(parentMove *Move, ply int) error {
 	p.scan() // Consume '('
 	var variationMoves []Move
-	if err := p.parseMovetext(&variationMoves); err != nil {
+	if err := p.parseLine(&variationMoves, parentMove, &parentMove.Comments, ply); err != nil {
 		return err
 	}
 
@@ -273,6 +853,11 @@ func (p *Parser) parseRAV(parentMove *Move) error {
 	}
 	p.scan() // Consume ')'
 
+	// A variation without moves, such as "({A note})", adds nothing to the
+	// tree: its comments are already attached to parentMove.
+	if len(variationMoves) == 0 {
+		return nil
+	}
 	if parentMove.Variations == nil {
 		parentMove.Variations = make([][]Move, 0)
 	}
@@ -280,11 +865,60 @@ func (p *Parser) parseRAV(parentMove *Move) error {
 	return nil
 }
 
+// skipToken offers the current token to the configured TokenErrorHandler
+// and, if the handler accepts, records a warning and consumes the token. It
+// reports whether the token was skipped.
+func (p *Parser) skipToken() bool {
+	if p.config.TokenErrorHandler == nil {
+		return false
+	}
+	pos := p.s.Pos()
+	if !p.config.TokenErrorHandler(Token{Kind: tokenKinds[p.tok.Type], Literal: p.tok.Literal, Line: pos.Line, Column: pos.Column}) {
+		return false
+	}
+	p.warnf("line %d, column %d: skipped unexpected token %q", pos.Line, pos.Column, p.tok.Literal)
+	p.scan()
+	return true
+}
+
+// warnf records a recoverable problem on the game being parsed.
+func (p *Parser) warnf(format string, args ...interface{}) {
+	p.game.ParseWarnings = append(p.game.ParseWarnings, fmt.Sprintf(format, args...))
+}
+
+// addComment consumes the current COMMENT token, attaching its text to dst
+// on behalf of move or passing it to the configured CommentHandler.
+func (p *Parser) addComment(move *Move, dst *[]string) {
+	lit := p.tok.Literal
+	if p.src != nil {
+		start, end := p.s.CommentSpan()
+		if move != nil && !p.config.SkipComments && p.config.CommentHandler == nil {
+			move.commentSrc = p.src
+			move.commentSpans = append(move.commentSpans, commentSpan{start: start, end: end})
+			p.scan() // Consume the comment
+			return
+		}
+		lit = string(p.src[start:end])
+	}
+	text := strings.TrimSpace(lit)
+	if p.config.CollapseCommentWhitespace {
+		text = strings.Join(strings.Fields(text), " ")
+	}
+	switch {
+	case p.config.SkipComments:
+	case p.config.CommentHandler != nil:
+		p.config.CommentHandler(move, text)
+	default:
+		*dst = append(*dst, text)
+	}
+	p.scan() // Consume the comment
+}
+
 func isResult(tok scanner.Token) bool {
 	if tok.Type == scanner.ASTERISK {
 		return true
 	}
-	if tok.Type == scanner.IDENT && (tok.Literal == "1-0" || tok.Literal == "0-1" || tok.Literal == "1/2-1/2") {
+	if tok.Type == scanner.IDENT && (tok.Literal == "1-0" || tok.Literal == "0-1" || tok.Literal == "1/2-1/2" || tok.Literal == "½-½") {
 		return true
 	}
 	return false
@@ -293,6 +927,14 @@ func isResult(tok scanner.Token) bool {
 func (p *Parser) parseMove() (Move, error) {
 	raw := p.tok.Literal
 	move, ok := p.parseMoveFromRaw(raw)
+	if !ok && !p.config.Strict {
+		// Some exporters split a move across tokens, as in "e 4" or "N f3".
+		// In lax mode, join the pieces if together they form a valid move.
+		p.scan()
+		if (p.tok.Type == scanner.IDENT || p.tok.Type == scanner.NUMBER) && !isResult(p.tok) {
+			move, ok = p.parseMoveFromRaw(raw + p.tok.Literal)
+		}
+	}
 	if !ok {
 		return Move{}, fmt.Errorf("invalid move: %s", raw)
 	}
@@ -319,6 +961,9 @@ func (p *Parser) parseMoveFromRaw(raw string) (Move, bool) {
 		}
 
 		promoChar := rune(promoAndSuffix[0])
+		if p.config.CaseInsensitivePieces {
+			promoChar = unicode.ToUpper(promoChar)
+		}
 		if piece, ok := PieceSymbols[promoChar]; ok {
 			finalMove.Promotion = piece
 		} else {
@@ -346,24 +991,42 @@ func (p *Parser) parseMoveFromRaw(raw string) (Move, bool) {
 			finalMove.IsMate = true
 			movetext = strings.TrimSuffix(movetext, "#")
 		}
+
+		// Some engines omit the "=" of a promotion, as in "e8Q" or "exd8Q+".
+		// Only a pawn move, which starts with a file, to the first or last
+		// rank qualifies, so piece moves such as "Qe8" are unaffected.
+		if n := len(movetext); n >= 3 && movetext[0] >= 'a' && movetext[0] <= 'h' && (movetext[n-2] == '1' || movetext[n-2] == '8') {
+			promoChar := rune(movetext[n-1])
+			if p.config.CaseInsensitivePieces {
+				promoChar = unicode.ToUpper(promoChar)
+			}
+			if piece, ok := PieceSymbols[promoChar]; ok && piece != King {
+				finalMove.Promotion = piece
+				movetext = movetext[:n-1]
+			}
+		}
 	}
 
 	// 3. Parse the core move notation that's left.
 	var coreMove Move
 	var ok bool
 
+	// Castling is also commonly written with zeros, as in "0-0".
 	switch movetext {
-	case "O-O":
+	case "O-O", "0-0":
 		coreMove.Piece = King
 		coreMove.IsKingsideCastle = true
 		ok = true
-	case "O-O-O":
+	case "O-O-O", "0-0-0":
 		coreMove.Piece = King
 		coreMove.IsQueensideCastle = true
 		ok = true
 	default:
 		// If not castling, parse as a regular move.
 		coreMove, ok = p.parseCoreMove(movetext)
+		if !ok && p.config.CaseInsensitivePieces && movetext != "" && strings.ContainsRune("nbrqk", rune(movetext[0])) {
+			coreMove, ok = p.parseCoreMove(strings.ToUpper(movetext[:1]) + movetext[1:])
+		}
 	}
 
 	if !ok {
@@ -379,6 +1042,10 @@ func (p *Parser) parseMoveFromRaw(raw string) (Move, bool) {
 
 // parseCoreMove handles a move string after any suffixes/promotions have been removed.
 func (p *Parser) parseCoreMove(raw string) (Move, bool) {
+	if move, ok := parseLongAlgebraic(raw); ok {
+		return move, true
+	}
+
 	// A pawn capture is the only case where the move starts with a file and contains a capture.
 	// e.g. "exd5". Let's handle this special case first.
 	if len(raw) == 4 && util.IsFile(rune(raw[0])) && raw[1] == 'x' {
@@ -387,10 +1054,11 @@ func (p *Parser) parseCoreMove(raw string) (Move, bool) {
 			return Move{}, false // Should not happen if grammar is correct
 		}
 		return Move{
-			Piece:     Pawn,
-			From:      Square{File: int(raw[0] - 'a')},
-			To:        dest,
-			IsCapture: true,
+			Piece:       Pawn,
+			From:        Square{File: int(raw[0] - 'a')},
+			HasFromFile: true,
+			To:          dest,
+			IsCapture:   true,
 		}, true
 	}
 
@@ -411,8 +1079,11 @@ func (p *Parser) parseCoreMove(raw string) (Move, bool) {
 
 	// Identify and parse the rest of the move components from the prefix.
 	movetext, move.Piece = parsePiece(movetext)
-	movetext, fromSquare := parseDisambiguation(movetext)
-	move.From = fromSquare
+	movetext, move.From, move.HasFromFile, move.HasFromRank = parseDisambiguation(movetext)
+	if move.Piece == Pawn && (move.HasFromFile || move.HasFromRank) {
+		// Only pawn captures name a starting file, and those are handled above.
+		return Move{}, false
+	}
 
 	// Check for a capture for piece moves, e.g. "x" in "Nxf3" or "Rdxf8"
 	if len(movetext) > 0 && movetext[0] == 'x' {
@@ -428,6 +1099,37 @@ func (p *Parser) parseCoreMove(raw string) (Move, bool) {
 	return move, true
 }
 
+// parseLongAlgebraic parses a move in long algebraic notation, which names
+// both squares separated by '-' for a quiet move or 'x' for a capture, as in
+// "e2-e4", "Ng1-f3" or "e4xd5". Both components of From are recorded for a
+// piece move. A pawn move is stored as its SAN would be, with only the file
+// of a capture, so that it is written back as SAN a parser accepts.
+func parseLongAlgebraic(raw string) (Move, bool) {
+	rest, piece := parsePiece(raw)
+	if len(rest) != 5 || (rest[2] != '-' && rest[2] != 'x') {
+		return Move{}, false
+	}
+	from, okFrom := newSquare(rest[:2])
+	to, okTo := newSquare(rest[3:])
+	if !okFrom || !okTo {
+		return Move{}, false
+	}
+	move := Move{Piece: piece, To: to, IsCapture: rest[2] == 'x'}
+	if piece != Pawn {
+		move.From, move.HasFromFile, move.HasFromRank = from, true, true
+		return move, true
+	}
+	// A pawn pushes along its file and captures on an adjacent one.
+	switch df := from.File - to.File; {
+	case !move.IsCapture && df == 0:
+	case move.IsCapture && (df == 1 || df == -1):
+		move.From, move.HasFromFile = Square{File: from.File}, true
+	default:
+		return Move{}, false
+	}
+	return move, true
+}
+
 func parsePiece(movetext string) (string, PieceType) {
 	if len(movetext) > 0 {
 		if piece, ok := PieceSymbols[rune(movetext[0])]; ok {
@@ -437,30 +1139,25 @@ func parsePiece(movetext string) (string, PieceType) {
 	return movetext, Pawn
 }
 
-func parseDisambiguation(movetext string) (string, Square) {
+func parseDisambiguation(movetext string) (string, Square, bool, bool) {
 	from := Square{}
-	if len(movetext) == 0 {
-		return movetext, from
-	}
+	hasFile, hasRank := false, false
 
-	// It can't be a capture 'x' at this stage. If it is, it's part of
-	// the next parsing step.
-	if movetext[0] == 'x' {
-		return movetext, from
+	// Disambiguation is a file, a rank, or both (e.g. "Qh4e1"), in that
+	// order. It can't be a capture 'x' at this stage; if present, that is
+	// part of the next parsing step.
+	if len(movetext) > 0 && util.IsFile(rune(movetext[0])) {
+		from.File = int(movetext[0] - 'a')
+		hasFile = true
+		movetext = movetext[1:]
 	}
-
-	// Disambiguation can be one char (file or rank) or two chars (file and rank).
-	// But we don't handle the two-char case yet (e.g. "R1a2").
-	char := rune(movetext[0])
-	if util.IsFile(char) {
-		from.File = int(char - 'a')
-		return movetext[1:], from
-	} else if util.IsRank(char) {
-		from.Rank = int(char - '1')
-		return movetext[1:], from
+	if len(movetext) > 0 && util.IsRank(rune(movetext[0])) {
+		from.Rank = int(movetext[0] - '1')
+		hasRank = true
+		movetext = movetext[1:]
 	}
 
-	return movetext, from
+	return movetext, from, hasFile, hasRank
 }
 
 func newSquare(s string) (Square, bool) {
@@ -484,3 +1181,34 @@ func ParseString(s string, opts ...ParserOption) (*Game, error) {
 	p := NewParser(strings.NewReader(s), opts...)
 	return p.Parse()
 }
+
+// ParseBytes is a convenience function to parse a PGN held in a byte slice,
+// such as the contents of a file read with os.ReadFile. It scans b in place
+// rather than through a reader, which is faster than ParseString for large
+// inputs. b must not be modified while ParseBytes runs.
+func ParseBytes(b []byte, opts ...ParserOption) (*Game, error) {
+	b = bytes.TrimPrefix(b, []byte("\uFEFF"))
+	p := newParser(scanner.NewScannerFromBytes(b), opts...)
+	return p.Parse()
+}
+
+// ParseMovetext parses a fragment of movetext without tags, such as
+// "1. e4 e5 2. Nf3", and returns its moves. A result token is accepted but
+// not required, since lax parsing is enabled before opts are applied. It
+// returns an error if the fragment contains a tag section or more than one
+// game.
+func ParseMovetext(s string, opts ...ParserOption) ([]Move, error) {
+	s = strings.TrimPrefix(s, "\uFEFF")
+	p := NewParser(strings.NewReader(s), append([]ParserOption{WithLaxParsing()}, opts...)...)
+	if p.tok.Type == scanner.LBRACKET {
+		return nil, fmt.Errorf("unexpected tag section in movetext")
+	}
+	game, err := p.Parse()
+	if err != nil {
+		return nil, err
+	}
+	if game.Trailing {
+		return nil, fmt.Errorf("unexpected content after movetext: %v", p.tok)
+	}
+	return game.Moves, nil
+}
@@ -0,0 +1,80 @@
+package chessnote
+
+// Equal reports whether m and other describe the same move: the same piece
+// moving to the same square, with the same promotion or castling. The
+// starting file and rank are only compared when both moves record them, so
+// "Nd2" equals "Nbd2". Capture, check and mate markers, annotations,
+// comments, variations and timestamps are ignored.
+func (m Move) Equal(other Move) bool {
+	if m.IsKingsideCastle || m.IsQueensideCastle || other.IsKingsideCastle || other.IsQueensideCastle {
+		return m.IsKingsideCastle == other.IsKingsideCastle && m.IsQueensideCastle == other.IsQueensideCastle
+	}
+	if m.Piece != other.Piece || m.To != other.To || m.Promotion != other.Promotion {
+		return false
+	}
+	if m.HasFromFile && other.HasFromFile && m.From.File != other.From.File {
+		return false
+	}
+	if m.HasFromRank && other.HasFromRank && m.From.Rank != other.From.Rank {
+		return false
+	}
+	return true
+}
+
+// FirstDeviation returns the 1-based ply at which the game's mainline first
+// differs from the reference line, such as a line of opening theory, with
+// moves compared by Move.Equal. It returns -1 if the game never leaves the
+// reference: either every reference move was played, or the game ended
+// while still following it.
+func FirstDeviation(game *Game, reference []Move) int {
+	for i, m := range game.Moves {
+		if i >= len(reference) {
+			return -1
+		}
+		if !m.Equal(reference[i]) {
+			return i + 1
+		}
+	}
+	return -1
+}
+
+// EqualMoves reports whether the two games play the same mainline from the
+// same starting position, for deduplicating databases whose tags and
+// results are unreliable. Tags, results, comments, NAGs and variations are
+// ignored, and moves are compared once their origins are resolved on the
+// board, so "Nd2" and "Nbd2" are the same move whenever the position makes
+// them so. Starting positions are compared as by Board.Hash, ignoring the
+// move counters. If a move cannot be played, it and the rest of both
+// mainlines are compared with Move.Equal instead.
+func (g *Game) EqualMoves(other *Game) bool {
+	if len(g.Moves) != len(other.Moves) {
+		return false
+	}
+	b, err := g.InitialBoard()
+	if err != nil {
+		return false
+	}
+	start, err := other.InitialBoard()
+	if err != nil || !samePosition(b, start) {
+		return false
+	}
+	for i, m := range g.Moves {
+		a, errA := m.ResolveFrom(b)
+		o, errO := other.Moves[i].ResolveFrom(b)
+		if errA != nil || errO != nil {
+			for j := i; j < len(g.Moves); j++ {
+				if !g.Moves[j].Equal(other.Moves[j]) {
+					return false
+				}
+			}
+			return true
+		}
+		if a.From != o.From || a.To != o.To || a.Promotion != o.Promotion || a.IsCastle() != o.IsCastle() {
+			return false
+		}
+		if err := b.Apply(a); err != nil {
+			return false
+		}
+	}
+	return true
+}
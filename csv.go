@@ -0,0 +1,101 @@
+package chessnote
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// csvHeader is the header row written by WriteCSV.
+var csvHeader = []string{"ply", "side", "san", "from", "to", "piece", "capture", "check", "mate", "nags", "comment"}
+
+// WriteCSV writes the game's mainline to w as comma-separated values, for
+// loading into a spreadsheet or data frame. After a header row, it writes
+// one row per move with these columns:
+//
+//   - ply: the 1-based ply, counted from the start of the game
+//   - side: "white" or "black"
+//   - san: the move in Standard Algebraic Notation
+//   - from, to: the squares the piece moves between, such as "g1" and "f3";
+//     for castling, the king's squares
+//   - piece: the piece moved, such as "knight"
+//   - capture, check, mate: "true" or "false"
+//   - nags: the move's NAGs, such as "$1 $14"
+//   - comment: the move's comments, joined by a space
+//
+// The origin square is found by replaying the game. If a move cannot be
+// replayed, its from column, and those of the moves after it, are left
+// empty, as is the to column of a castling move.
+func (g *Game) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+
+	offset := fenPlyOffset(g.Tags["FEN"])
+	b, err := g.InitialBoard()
+	replayed := err == nil
+	for i, m := range g.Moves {
+		side := "white"
+		if (offset+i)%2 == 1 {
+			side = "black"
+		}
+
+		var from, to string
+		if !m.IsKingsideCastle && !m.IsQueensideCastle {
+			to = squareName(m.To)
+		}
+		if replayed {
+			from, to = b.csvSquares(m, to)
+			replayed = b.Apply(m) == nil
+			if !replayed {
+				from = ""
+			}
+		}
+
+		nags := make([]string, len(m.NAGs))
+		for j, nag := range m.NAGs {
+			nags[j] = "$" + strconv.Itoa(nag)
+		}
+		row := []string{
+			strconv.Itoa(i + 1),
+			side,
+			m.String(),
+			from,
+			to,
+			pieceName(m.Piece),
+			strconv.FormatBool(m.IsCapture || m.IsEnPassant),
+			strconv.FormatBool(m.IsCheck),
+			strconv.FormatBool(m.IsMate),
+			strings.Join(nags, " "),
+			m.Comment(),
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvSquares returns the from and to columns of m, played in b, for
+// WriteCSV. to is the destination already known from the move itself.
+func (b *Board) csvSquares(m Move, to string) (string, string) {
+	if m.IsKingsideCastle || m.IsQueensideCastle {
+		king, ok := b.kingSquare(b.turn)
+		if !ok {
+			return "", ""
+		}
+		dest := Square{File: 6, Rank: king.Rank}
+		if m.IsQueensideCastle {
+			dest.File = 2
+		}
+		return squareName(king), squareName(dest)
+	}
+	from, err := b.resolveOrigin(m)
+	if err != nil {
+		return "", to
+	}
+	return squareName(from), to
+}
@@ -0,0 +1,125 @@
+package chessnote
+
+import "io"
+
+// GameCursor steps through the mainline of a game in both directions, as
+// when a user scrubs through a game in a viewer. It keeps a single board up
+// to date incrementally: moving forward plays one move, and moving back
+// undoes it from a record of what the move changed, so neither direction
+// replays the game from its start.
+//
+// The game's mainline must not change while a cursor is in use.
+type GameCursor struct {
+	game    *Game
+	board   *Board
+	history []undoRecord // One record per ply played, most recent last.
+}
+
+// undoRecord holds what is needed to take back a move: the squares it
+// changed and the parts of the position a move cannot be undone from.
+type undoRecord struct {
+	castle     bool   // Whether the move was castling.
+	from, to   Square // The squares of the piece moved, or of the king for castling.
+	rookFrom   Square // The squares of the rook, for castling.
+	rookTo     Square
+	moved      Piece // The piece on from before the move, a pawn if it promoted.
+	captured   Piece
+	capturedAt Square // Differs from to for an en passant capture.
+
+	castling       castlingRights
+	epTarget       Square
+	hasEPTarget    bool
+	halfmoveClock  int
+	fullmoveNumber int
+}
+
+// NewGameCursor returns a cursor positioned at the start of the game, before
+// its first move. It returns an error if the game's initial position cannot
+// be built, such as when its FEN tag is invalid.
+func NewGameCursor(g *Game) (*GameCursor, error) {
+	b, err := g.InitialBoard()
+	if err != nil {
+		return nil, err
+	}
+	return &GameCursor{game: g, board: b, history: make([]undoRecord, 0, len(g.Moves))}, nil
+}
+
+// Ply returns the number of mainline moves played to reach the current
+// position; 0 is the game's initial position.
+func (c *GameCursor) Ply() int {
+	return len(c.history)
+}
+
+// Board returns the current position. The board is updated in place by
+// Forward and Back, so callers that keep a position must copy it, and must
+// not modify it.
+func (c *GameCursor) Board() *Board {
+	return c.board
+}
+
+// Forward plays the next mainline move. It returns io.EOF at the end of the
+// mainline and a *ReplayError if the move is illegal, leaving the position
+// unchanged in both cases.
+func (c *GameCursor) Forward() error {
+	ply := len(c.history)
+	if ply == len(c.game.Moves) {
+		return io.EOF
+	}
+	m := c.game.Moves[ply]
+	b := c.board
+	rec := undoRecord{
+		castling:       b.castling,
+		epTarget:       b.epTarget,
+		hasEPTarget:    b.hasEPTarget,
+		halfmoveClock:  b.halfmoveClock,
+		fullmoveNumber: b.fullmoveNumber,
+	}
+	if m.IsKingsideCastle || m.IsQueensideCastle {
+		rec.from, rec.to, rec.rookFrom, rec.rookTo, _ = b.castlingSquares(m.IsKingsideCastle)
+		if err := b.applyCastle(m.IsKingsideCastle); err != nil {
+			return newReplayError(b, ply+1, m, err)
+		}
+		rec.castle = true
+	} else {
+		from, err := b.resolveOrigin(m)
+		if err != nil {
+			return newReplayError(b, ply+1, m, err)
+		}
+		rec.from, rec.to, rec.capturedAt = from, m.To, m.To
+		if b.isEnPassant(from, m) {
+			rec.capturedAt = Square{File: m.To.File, Rank: from.Rank}
+		}
+		rec.moved, rec.captured = b.PieceAt(from), b.PieceAt(rec.capturedAt)
+		b.applyResolved(from, m)
+	}
+	c.history = append(c.history, rec)
+	return nil
+}
+
+// Back takes back the last move played, returning false if the cursor is
+// already at the game's initial position.
+func (c *GameCursor) Back() bool {
+	n := len(c.history)
+	if n == 0 {
+		return false
+	}
+	rec := c.history[n-1]
+	c.history = c.history[:n-1]
+
+	b := c.board
+	b.turn = b.turn.Opponent()
+	if rec.castle {
+		b.squares[rec.to.Rank][rec.to.File] = Piece{}
+		b.squares[rec.rookTo.Rank][rec.rookTo.File] = Piece{}
+		b.squares[rec.from.Rank][rec.from.File] = Piece{Type: King, Color: b.turn}
+		b.squares[rec.rookFrom.Rank][rec.rookFrom.File] = Piece{Type: Rook, Color: b.turn}
+	} else {
+		b.squares[rec.to.Rank][rec.to.File] = Piece{}
+		b.squares[rec.capturedAt.Rank][rec.capturedAt.File] = rec.captured
+		b.squares[rec.from.Rank][rec.from.File] = rec.moved
+	}
+	b.castling = rec.castling
+	b.epTarget, b.hasEPTarget = rec.epTarget, rec.hasEPTarget
+	b.halfmoveClock, b.fullmoveNumber = rec.halfmoveClock, rec.fullmoveNumber
+	return true
+}
@@ -0,0 +1,53 @@
+package chessnote
+
+import (
+	"io"
+	"strconv"
+	"strings"
+)
+
+// DumpTree writes a readable, indented listing of the game's moves to w,
+// one move per line, for use when debugging or writing test fixtures. Every
+// move is numbered ("1. e4", "1... e5") from its ply, taking a FEN tag into
+// account, and followed by its NAGs and comments. Each variation is listed
+// directly below the move it replaces, indented by two more spaces.
+//
+// The format is meant for people and may change; use ToPGN for output that
+// has to be read back.
+func (g *Game) DumpTree(w io.Writer) error {
+	var sb strings.Builder
+	dumpLine(&sb, g.Moves, fenPlyOffset(g.Tags["FEN"]), 0)
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// dumpLine writes a line of moves, whose first move is played at firstPly
+// counted from zero, and its variations at the given depth.
+func dumpLine(sb *strings.Builder, moves []Move, firstPly, depth int) {
+	indent := strings.Repeat("  ", depth)
+	for i, m := range moves {
+		ply := firstPly + i
+		sb.WriteString(indent)
+		sb.WriteString(strconv.Itoa(ply/2 + 1))
+		if ply%2 == 0 {
+			sb.WriteString(". ")
+		} else {
+			sb.WriteString("... ")
+		}
+		sb.WriteString(m.String())
+		for _, nag := range m.NAGs {
+			sb.WriteString(" $")
+			sb.WriteString(strconv.Itoa(nag))
+		}
+		for _, c := range m.comments() {
+			sb.WriteString(" {")
+			sb.WriteString(c)
+			sb.WriteByte('}')
+		}
+		sb.WriteByte('\n')
+
+		for _, variation := range m.Variations {
+			dumpLine(sb, variation, ply, depth+1)
+		}
+	}
+}
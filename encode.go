@@ -0,0 +1,120 @@
+package chessnote
+
+import (
+	"io"
+	"strconv"
+	"strings"
+)
+
+// maxLineLength is the column at which ToPGN wraps movetext, as recommended
+// by the PGN export format.
+const maxLineLength = 80
+
+// tagValueEscaper escapes the characters that cannot appear unescaped in a
+// PGN string: the quote and the backslash.
+var tagValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// ToPGN encodes the game as PGN text. Tags are written in the order given by
+// TagKeys, followed by the movetext: the game's leading comments, the moves
+// with their NAGs, comments and variations, the result, and any comments
+// after the result. Movetext lines are wrapped at 80 columns and the text
+// ends with a newline.
+//
+// Move numbers are derived from each move's ply, taking a FEN tag into
+// account, and Black moves are numbered ("12...") wherever they start a line
+// or follow a comment or variation. The result token is the game's Result,
+// or its Result tag if the movetext had none, or "*". Quotes and backslashes
+// in tag values are escaped with a backslash. Comments are written in
+// braces, so a comment that itself contains '}' cannot be read back.
+func (g *Game) ToPGN() string {
+	var sb strings.Builder
+	for _, key := range g.TagKeys() {
+		sb.WriteByte('[')
+		sb.WriteString(key)
+		sb.WriteString(` "`)
+		tagValueEscaper.WriteString(&sb, g.Tags[key])
+		sb.WriteString("\"]\n")
+	}
+	if len(g.Tags) > 0 {
+		sb.WriteByte('\n')
+	}
+
+	var tokens []string
+	tokens = appendComments(tokens, g.Comments)
+	tokens = appendLine(tokens, g.Moves, fenPlyOffset(g.Tags["FEN"]))
+	result := g.resultString()
+	if ParseOutcome(result) == OutcomeUnknown {
+		result = "*"
+	}
+	tokens = append(tokens, strings.TrimSpace(result))
+	tokens = appendComments(tokens, g.ResultComments)
+
+	column := 0
+	for _, tok := range tokens {
+		if column > 0 && column+1+len(tok) > maxLineLength {
+			sb.WriteByte('\n')
+			column = 0
+		} else if column > 0 {
+			sb.WriteByte(' ')
+			column++
+		}
+		sb.WriteString(tok)
+		if i := strings.LastIndexByte(tok, '\n'); i >= 0 {
+			column = len(tok) - i - 1
+		} else {
+			column += len(tok)
+		}
+	}
+	sb.WriteByte('\n')
+	return sb.String()
+}
+
+// WritePGN writes the game to w as PGN text, exactly as returned by ToPGN.
+func (g *Game) WritePGN(w io.Writer) error {
+	_, err := io.WriteString(w, g.ToPGN())
+	return err
+}
+
+// appendLine appends the movetext tokens of a line of moves, whose first move
+// is played at the given ply counted from the start of the game, including
+// any plies before its initial position. A variation is appended with its
+// parentheses attached to its first and last tokens.
+func appendLine(tokens []string, moves []Move, firstPly int) []string {
+	needNumber := true
+	for i, m := range moves {
+		ply := firstPly + i
+		switch {
+		case ply%2 == 0:
+			tokens = append(tokens, strconv.Itoa(ply/2+1)+".")
+		case needNumber:
+			tokens = append(tokens, strconv.Itoa(ply/2+1)+"...")
+		}
+		tokens = append(tokens, m.String())
+		for _, nag := range m.NAGs {
+			tokens = append(tokens, "$"+strconv.Itoa(nag))
+		}
+		comments := m.comments()
+		tokens = appendComments(tokens, comments)
+		needNumber = len(comments) > 0 || len(m.Variations) > 0
+
+		for _, variation := range m.Variations {
+			sub := appendLine(nil, variation, ply)
+			if len(sub) == 0 {
+				tokens = append(tokens, "()")
+				continue
+			}
+			sub[0] = "(" + sub[0]
+			sub[len(sub)-1] += ")"
+			tokens = append(tokens, sub...)
+		}
+	}
+	return tokens
+}
+
+// appendComments appends each comment as a brace-delimited token.
+func appendComments(tokens []string, comments []string) []string {
+	for _, c := range comments {
+		tokens = append(tokens, "{"+c+"}")
+	}
+	return tokens
+}
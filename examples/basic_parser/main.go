@@ -47,12 +47,6 @@ func main() {
 
 	// Now that we have the parsed game, we can inspect its data.
 	fmt.Println("\n--- Game Information ---")
-	fmt.Printf("Event: %s\n", game.Tags["Event"])
-	fmt.Printf("Site: %s\n", game.Tags["Site"])
-	fmt.Printf("Date: %s\n", game.Tags["Date"])
-	fmt.Printf("White: %s\n", game.Tags["White"])
-	fmt.Printf("Black: %s\n", game.Tags["Black"])
-	fmt.Printf("Result: %s\n", game.Result)
-	fmt.Printf("Total Moves: %d\n", len(game.Moves))
+	fmt.Print(game.Summary())
 	fmt.Println("----------------------")
 }
@@ -0,0 +1,267 @@
+package chessnote
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// fenSymbols maps the FEN letter of a piece to its type. Uppercase letters
+// denote White pieces and lowercase letters Black pieces.
+var fenSymbols = map[rune]PieceType{
+	'p': Pawn,
+	'n': Knight,
+	'b': Bishop,
+	'r': Rook,
+	'q': Queen,
+	'k': King,
+}
+
+// StartingFEN is the FEN of the standard starting position.
+const StartingFEN = "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1"
+
+// ParseFEN parses a position in Forsyth-Edwards Notation, as found in the
+// FEN tag of games that do not start from the standard position. Some tools
+// truncate a FEN after its en passant field; the missing halfmove clock and
+// fullmove number then default to 0 and 1. For Chess960, castling rights may
+// also name the file of the castling rook, as in Shredder-FEN ("HAha") or
+// X-FEN; Board.FEN writes them back as KQkq.
+func ParseFEN(fen string) (*Board, error) {
+	fields := strings.Fields(fen)
+	if len(fields) == 4 {
+		fields = append(fields, "0", "1")
+	}
+	if len(fields) != 6 {
+		return nil, fmt.Errorf("invalid FEN %q: expected 4 or 6 fields, got %d", fen, len(fields))
+	}
+
+	b := &Board{}
+	if err := b.parsePlacement(fields[0]); err != nil {
+		return nil, fmt.Errorf("invalid FEN %q: %w", fen, err)
+	}
+
+	switch fields[1] {
+	case "w":
+		b.turn = White
+	case "b":
+		b.turn = Black
+	default:
+		return nil, fmt.Errorf("invalid FEN %q: invalid side to move %q", fen, fields[1])
+	}
+
+	if fields[2] != "-" {
+		for _, r := range fields[2] {
+			switch r {
+			case 'K':
+				b.castling |= whiteKingside
+			case 'Q':
+				b.castling |= whiteQueenside
+			case 'k':
+				b.castling |= blackKingside
+			case 'q':
+				b.castling |= blackQueenside
+			default:
+				right, ok := b.fileCastlingRight(r)
+				if !ok {
+					return nil, fmt.Errorf("invalid FEN %q: invalid castling rights %q", fen, fields[2])
+				}
+				b.castling |= right
+			}
+		}
+	}
+
+	if fields[3] != "-" {
+		sq, ok := newSquare(fields[3])
+		if !ok {
+			return nil, fmt.Errorf("invalid FEN %q: invalid en passant square %q", fen, fields[3])
+		}
+		b.epTarget, b.hasEPTarget = sq, true
+	}
+
+	halfmove, err := strconv.Atoi(fields[4])
+	if err != nil || halfmove < 0 {
+		return nil, fmt.Errorf("invalid FEN %q: invalid halfmove clock %q", fen, fields[4])
+	}
+	fullmove, err := strconv.Atoi(fields[5])
+	if err != nil || fullmove < 1 {
+		return nil, fmt.Errorf("invalid FEN %q: invalid fullmove number %q", fen, fields[5])
+	}
+	b.halfmoveClock, b.fullmoveNumber = halfmove, fullmove
+	return b, nil
+}
+
+// fileCastlingRight returns the castling right named by a rook's file
+// letter in the castling field, uppercase for White and lowercase for Black.
+// The side is the one the rook stands on relative to its king; castling is
+// then played with the outermost rook on that side (see castlingSquares).
+// It reports false if the letter is not a file, or no king and rook of that
+// color stand on their back rank as it describes.
+func (b *Board) fileCastlingRight(r rune) (castlingRights, bool) {
+	c, file := White, int(r-'A')
+	if unicode.IsLower(r) {
+		c, file = Black, int(r-'a')
+	}
+	if file < 0 || file > 7 {
+		return 0, false
+	}
+	kingFile, ok := b.backRankKing(c)
+	if !ok || file == kingFile || b.squares[backRank(c)][file] != (Piece{Type: Rook, Color: c}) {
+		return 0, false
+	}
+	return castlingRight(c, file > kingFile), true
+}
+
+// fenPlyOffset returns the number of plies played before the position
+// described by fen, as implied by its side-to-move and fullmove fields. A
+// FEN truncated to four fields counts from move 1, as in ParseFEN. It
+// returns 0 for an empty or malformed FEN.
+func fenPlyOffset(fen string) int {
+	fields := strings.Fields(fen)
+	if len(fields) == 4 {
+		fields = append(fields, "0", "1")
+	}
+	if len(fields) < 6 {
+		return 0
+	}
+	fullmove, err := strconv.Atoi(fields[5])
+	if err != nil || fullmove < 1 {
+		return 0
+	}
+	offset := (fullmove - 1) * 2
+	if fields[1] == "b" {
+		offset++
+	}
+	return offset
+}
+
+// parsePlacement fills the board from the piece placement field of a FEN.
+func (b *Board) parsePlacement(placement string) error {
+	rows := strings.Split(placement, "/")
+	if len(rows) != 8 {
+		return fmt.Errorf("piece placement must have 8 ranks, got %d", len(rows))
+	}
+	for i, row := range rows {
+		rank := 7 - i
+		file := 0
+		for _, r := range row {
+			if r >= '1' && r <= '8' {
+				file += int(r - '0')
+				continue
+			}
+			lower := unicode.ToLower(r)
+			piece, ok := fenSymbols[lower]
+			if !ok {
+				return fmt.Errorf("invalid piece %q", r)
+			}
+			if file >= 8 {
+				return fmt.Errorf("rank %d describes more than 8 squares", rank+1)
+			}
+			color := Black
+			if r != lower {
+				color = White
+			}
+			b.squares[rank][file] = Piece{Type: piece, Color: color}
+			file++
+		}
+		if file != 8 {
+			return fmt.Errorf("rank %d does not describe 8 squares", rank+1)
+		}
+	}
+	return nil
+}
+
+// FEN returns the position in Forsyth-Edwards Notation.
+func (b *Board) FEN() string {
+	return string(b.appendFEN(make([]byte, 0, maxFENLength)))
+}
+
+// WriteFEN writes the position in Forsyth-Edwards Notation to w, without the
+// trailing newline. Unlike FEN, it does not allocate a string, so it is
+// suited to exporting the positions of a large database in a tight loop.
+func (b *Board) WriteFEN(w io.Writer) error {
+	buf := fenBufferPool.Get().(*[]byte)
+	*buf = b.appendFEN((*buf)[:0])
+	_, err := w.Write(*buf)
+	fenBufferPool.Put(buf)
+	return err
+}
+
+// maxFENLength is the length of the longest FEN that does not have
+// improbably large move counters.
+const maxFENLength = 90
+
+// fenBufferPool holds the scratch buffers used by WriteFEN.
+var fenBufferPool = sync.Pool{
+	New: func() interface{} {
+		buf := make([]byte, 0, maxFENLength)
+		return &buf
+	},
+}
+
+// appendFEN appends the position in Forsyth-Edwards Notation to dst and
+// returns the extended buffer.
+func (b *Board) appendFEN(dst []byte) []byte {
+	for rank := 7; rank >= 0; rank-- {
+		empty := 0
+		for file := 0; file < 8; file++ {
+			piece := b.squares[rank][file]
+			if piece.IsEmpty() {
+				empty++
+				continue
+			}
+			if empty > 0 {
+				dst = append(dst, byte('0'+empty))
+				empty = 0
+			}
+			dst = append(dst, fenSymbol(piece))
+		}
+		if empty > 0 {
+			dst = append(dst, byte('0'+empty))
+		}
+		if rank > 0 {
+			dst = append(dst, '/')
+		}
+	}
+
+	if b.turn == Black {
+		dst = append(dst, " b "...)
+	} else {
+		dst = append(dst, " w "...)
+	}
+
+	if b.castling == 0 {
+		dst = append(dst, '-')
+	}
+	for _, right := range [...]struct {
+		flag   castlingRights
+		symbol byte
+	}{{whiteKingside, 'K'}, {whiteQueenside, 'Q'}, {blackKingside, 'k'}, {blackQueenside, 'q'}} {
+		if b.castling&right.flag != 0 {
+			dst = append(dst, right.symbol)
+		}
+	}
+
+	dst = append(dst, ' ')
+	if b.hasEPTarget {
+		dst = append(dst, byte('a'+b.epTarget.File), byte('1'+b.epTarget.Rank))
+	} else {
+		dst = append(dst, '-')
+	}
+
+	dst = append(dst, ' ')
+	dst = strconv.AppendInt(dst, int64(b.halfmoveClock), 10)
+	dst = append(dst, ' ')
+	dst = strconv.AppendInt(dst, int64(b.fullmoveNumber), 10)
+	return dst
+}
+
+// fenSymbol returns the FEN letter for a non-empty piece.
+func fenSymbol(p Piece) byte {
+	if p.Color == White {
+		return "PNBRQK"[p.Type]
+	}
+	return "pnbrqk"[p.Type]
+}
@@ -0,0 +1,218 @@
+package chessnote
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Subgame returns a new game whose mainline is the range of plies from
+// fromPly to toPly, inclusive, where ply 1 is the first move of the game.
+// The new game carries a copy of the original tags, with FEN and SetUp tags
+// describing the position before fromPly, so it can be replayed on its own,
+// and a PlyCount tag, if present, updated to the length of the range.
+// Its result is "*" unless the range reaches the end of the game, in which
+// case the original result is kept. Variations within the range are
+// preserved.
+func (g *Game) Subgame(fromPly, toPly int) (*Game, error) {
+	if fromPly < 1 || toPly > len(g.Moves) || fromPly > toPly {
+		return nil, fmt.Errorf("invalid ply range %d-%d for a game with %d plies", fromPly, toPly, len(g.Moves))
+	}
+	positions, err := g.Positions()
+	if err != nil {
+		return nil, err
+	}
+
+	result := "*"
+	if toPly == len(g.Moves) && g.Result != "" {
+		result = g.Result
+	}
+
+	sub := &Game{
+		Tags:   make(map[string]string, len(g.Tags)+2),
+		Moves:  copyMoves(g.Moves[fromPly-1 : toPly]),
+		Result: result,
+	}
+	for k, v := range g.Tags {
+		sub.Tags[k] = v
+	}
+	sub.Tags["SetUp"] = "1"
+	sub.Tags["FEN"] = positions[fromPly-1].FEN()
+	sub.Tags["Result"] = result
+	if _, ok := sub.Tags["PlyCount"]; ok {
+		sub.Tags["PlyCount"] = strconv.Itoa(len(sub.Moves))
+	}
+	return sub, nil
+}
+
+// LastMove returns the last move of the mainline, or false if the game has
+// no moves.
+func (g *Game) LastMove() (Move, bool) {
+	return g.MainlineMove(len(g.Moves))
+}
+
+// MainlineMove returns the mainline move played at ply, where ply 1 is the
+// first move of the game, or false if the game has no such move.
+func (g *Game) MainlineMove(ply int) (Move, bool) {
+	if ply < 1 || ply > len(g.Moves) {
+		return Move{}, false
+	}
+	return g.Moves[ply-1], true
+}
+
+// SplitAt divides the game's mainline after ply into two games: head holds
+// the first ply moves and tail the remaining ones. Both carry a copy of the
+// original tags. The tail starts from the position reached after ply, which
+// its FEN and SetUp tags describe as for Subgame, and keeps the original
+// result and the comments after it. The head keeps the comments before the
+// first move, its result is "*", and a PlyCount tag, if present, is updated
+// in both. A variation stays with the move it is an alternative to, so it
+// ends up in whichever half contains its branch point. ply must leave at
+// least one move in each half.
+func (g *Game) SplitAt(ply int) (head, tail *Game, err error) {
+	if ply < 1 || ply >= len(g.Moves) {
+		return nil, nil, fmt.Errorf("invalid split ply %d for a game with %d plies", ply, len(g.Moves))
+	}
+	tail, err = g.Subgame(ply+1, len(g.Moves))
+	if err != nil {
+		return nil, nil, err
+	}
+	tail.ResultComments = append([]string(nil), g.ResultComments...)
+
+	head = &Game{
+		Tags:     make(map[string]string, len(g.Tags)),
+		Moves:    copyMoves(g.Moves[:ply]),
+		Comments: append([]string(nil), g.Comments...),
+		Result:   "*",
+	}
+	for k, v := range g.Tags {
+		head.Tags[k] = v
+	}
+	head.Tags["Result"] = head.Result
+	if _, ok := head.Tags["PlyCount"]; ok {
+		head.Tags["PlyCount"] = strconv.Itoa(len(head.Moves))
+	}
+	return head, tail, nil
+}
+
+// OpeningLine returns the first maxPly mainline moves, or all of them if the
+// game is shorter, as a new slice for aggregating opening statistics or
+// building an opening book. The moves are stripped of their comments, NAGs,
+// variations and timestamps, so lines from different games can be compared
+// directly. It returns an empty slice if maxPly is not positive.
+func (g *Game) OpeningLine(maxPly int) []Move {
+	n := len(g.Moves)
+	if maxPly < n {
+		n = maxPly
+	}
+	if n < 0 {
+		n = 0
+	}
+	line := make([]Move, n)
+	for i, m := range g.Moves[:n] {
+		m.Comments, m.NAGs, m.Variations = nil, nil, nil
+		m.commentSrc, m.commentSpans = nil, nil
+		m.Timestamp = time.Time{}
+		line[i] = m
+	}
+	return line
+}
+
+// VariationsAsGames returns every variation of the game, including nested
+// variations, as a standalone game whose mainline is the variation, in
+// source order. Each game carries a copy of the original tags, with FEN and
+// SetUp tags describing the position where the variation branches off, a
+// PlyCount tag, if present, updated to the length of the variation and a
+// result of "*". Variations nested in the variation are kept in its moves
+// as well as returned on their own. A variation whose branch point cannot
+// be reached, because an earlier move is illegal, is left out.
+func (g *Game) VariationsAsGames() []*Game {
+	b, err := g.InitialBoard()
+	if err != nil {
+		return nil
+	}
+	var games []*Game
+	g.collectVariations(b, g.Moves, &games)
+	return games
+}
+
+// collectVariations appends a game for each variation of moves, and of the
+// moves of those variations, to games. b holds the position before the
+// first move and is advanced along the line.
+func (g *Game) collectVariations(b *Board, moves []Move, games *[]*Game) {
+	for _, m := range moves {
+		before := *b
+		if err := b.Apply(m); err != nil {
+			return
+		}
+		for _, variation := range m.Variations {
+			if len(variation) == 0 {
+				continue
+			}
+			v := &Game{
+				Tags:   make(map[string]string, len(g.Tags)+2),
+				Moves:  copyMoves(variation),
+				Result: "*",
+			}
+			for k, val := range g.Tags {
+				v.Tags[k] = val
+			}
+			v.Tags["SetUp"] = "1"
+			v.Tags["FEN"] = before.FEN()
+			v.Tags["Result"] = v.Result
+			if _, ok := v.Tags["PlyCount"]; ok {
+				v.Tags["PlyCount"] = strconv.Itoa(len(v.Moves))
+			}
+			*games = append(*games, v)
+
+			start := before
+			g.collectVariations(&start, variation, games)
+		}
+	}
+}
+
+// copyMoves returns a deep copy of moves, including their NAGs,
+// comments and variations, so the copy can be modified without affecting the original.
+func copyMoves(moves []Move) []Move {
+	if moves == nil {
+		return nil
+	}
+	copied := make([]Move, len(moves))
+	for i, m := range moves {
+		if m.Comments != nil {
+			m.Comments = append([]string(nil), m.Comments...)
+		}
+		if m.NAGs != nil {
+			m.NAGs = append([]int(nil), m.NAGs...)
+		}
+		if m.Variations != nil {
+			variations := make([][]Move, len(m.Variations))
+			for j, v := range m.Variations {
+				variations[j] = copyMoves(v)
+			}
+			m.Variations = variations
+		}
+		copied[i] = m
+	}
+	return copied
+}
+
+// CommentsByPly returns the comments of the game's mainline keyed by ply,
+// where ply 1 is the first move of the game, for looking up the note on a
+// given move without walking the move tree. Ply 0 holds the comments before
+// the first move. Several comments on the same move are joined by a space,
+// and plies without comments are absent. Only the mainline is covered:
+// comments inside variations are not included.
+func (g *Game) CommentsByPly() map[int]string {
+	comments := make(map[int]string)
+	if len(g.Comments) > 0 {
+		comments[0] = strings.Join(g.Comments, " ")
+	}
+	for i, m := range g.Moves {
+		if comment := m.Comment(); comment != "" {
+			comments[i+1] = comment
+		}
+	}
+	return comments
+}
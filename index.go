@@ -0,0 +1,290 @@
+package chessnote
+
+// FNV-1a parameters used by Board.Hash.
+const (
+	fnvOffset64 = 14695981039346656037
+	fnvPrime64  = 1099511628211
+)
+
+// Hash returns a 64-bit hash of the position. It covers the placement of the
+// pieces, the side to move, the castling rights and, if a pawn could capture
+// there, the en passant target square, which are the parts of a position
+// that make two positions the same for repetition purposes. The move
+// counters are ignored, so transpositions hash alike.
+//
+// Distinct positions may, very rarely, share a hash.
+func (b *Board) Hash() uint64 {
+	h := uint64(fnvOffset64)
+	mix := func(v byte) {
+		h ^= uint64(v)
+		h *= fnvPrime64
+	}
+	for rank := 0; rank < 8; rank++ {
+		for file := 0; file < 8; file++ {
+			p := b.squares[rank][file]
+			mix(byte(p.Color)<<4 | byte(p.Type))
+		}
+	}
+	mix(byte(b.turn))
+	mix(byte(b.castling))
+	if b.epCapturable() {
+		mix(byte(b.epTarget.Rank*8 + b.epTarget.File))
+	} else {
+		mix(0xff)
+	}
+	return h
+}
+
+// epCapturable reports whether the side to move has a pawn next to the pawn
+// that just advanced two squares, so the en passant target square matters.
+func (b *Board) epCapturable() bool {
+	if !b.hasEPTarget {
+		return false
+	}
+	rank := 4 // The rank of a pawn Black pushed, with White to move.
+	if b.turn == Black {
+		rank = 3
+	}
+	for _, df := range [...]int{-1, 1} {
+		sq := Square{File: b.epTarget.File + df, Rank: rank}
+		if onBoard(sq) && b.PieceAt(sq) == (Piece{Type: Pawn, Color: b.turn}) {
+			return true
+		}
+	}
+	return false
+}
+
+// Hashes returns the hash (see Board.Hash) of the position after each
+// mainline ply, so that element i follows move i. Two games that share
+// their first n hashes share an opening, and a hash common to two games at
+// different plies marks a transposition. The sequence stops at the first
+// illegal move, and is nil if the initial position cannot be built.
+func (g *Game) Hashes() []uint64 {
+	b, err := g.InitialBoard()
+	if err != nil {
+		return nil
+	}
+	hashes := make([]uint64, 0, len(g.Moves))
+	for _, m := range g.Moves {
+		if err := b.Apply(m); err != nil {
+			break
+		}
+		hashes = append(hashes, b.Hash())
+	}
+	return hashes
+}
+
+// Transpositions returns the groups of moves, in the mainline or in
+// variations, that lead to the same position by different routes, such as a
+// variation whose moves transpose into a line played elsewhere in the game.
+// Positions are compared by hash (see Board.Hash), so move counters are
+// ignored.
+//
+// Moves are identified by their node number: the moves of the game are
+// numbered from 0 in source order, each move followed by its variations
+// before the next move of the same line, which is the order in which
+// FindMoves reports them. The path of node n is therefore
+// g.FindMoves(func(Move) bool { return true })[n].Path. Each group lists its
+// nodes in ascending order, and groups are ordered by their first node.
+//
+// A position repeated along a single line is a repetition rather than a
+// transposition, so only its first occurrence on that line is counted. Each
+// line is replayed up to its first illegal move, and nothing is reported if
+// the initial position cannot be built.
+func (g *Game) Transpositions() [][]int {
+	b, err := g.InitialBoard()
+	if err != nil {
+		return nil
+	}
+	nodes := make(map[*Move]int)
+	numberMoves(g.Moves, nodes)
+
+	index := make(map[uint64]int)
+	var groups [][]MoveRef
+	replayMoves(b, g.Moves, nil, 1, func(ref MoveRef, _, after *Board) {
+		h := after.Hash()
+		i, ok := index[h]
+		if !ok {
+			index[h] = len(groups)
+			groups = append(groups, []MoveRef{ref})
+			return
+		}
+		for _, other := range groups[i] {
+			if sameLine(other.Path, ref.Path) {
+				return
+			}
+		}
+		groups[i] = append(groups[i], ref)
+	})
+
+	var transpositions [][]int
+	for _, group := range groups {
+		if len(group) < 2 {
+			continue
+		}
+		ids := make([]int, len(group))
+		for i, ref := range group {
+			m, _ := g.MoveAt(ref.Path)
+			ids[i] = nodes[m]
+		}
+		transpositions = append(transpositions, ids)
+	}
+	return transpositions
+}
+
+// numberMoves assigns the moves of a line and, recursively, their variations
+// their node numbers, continuing from the number of moves already in nodes.
+func numberMoves(moves []Move, nodes map[*Move]int) {
+	for i := range moves {
+		nodes[&moves[i]] = len(nodes)
+		for _, variation := range moves[i].Variations {
+			numberMoves(variation, nodes)
+		}
+	}
+}
+
+// sameLine reports whether the moves at paths a and b belong to the same
+// line of moves, that is, differ only in their final index.
+func sameLine(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := 0; i < len(a)-1; i++ {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ContainsPosition reports whether the mainline of the game reaches the
+// position given in Forsyth-Edwards Notation and, if so, the number of
+// plies played when it is first reached, 0 being the game's initial
+// position. Positions match as they do for Board.Hash, but are compared
+// exactly: the placement, side to move, castling rights and any en passant
+// square a pawn could capture on must agree, while the move counters are
+// ignored. It returns false if fen is not valid, and stops at the first
+// illegal move.
+func (g *Game) ContainsPosition(fen string) (bool, int) {
+	target, err := ParseFEN(fen)
+	if err != nil {
+		return false, 0
+	}
+	b, err := g.InitialBoard()
+	if err != nil {
+		return false, 0
+	}
+	if samePosition(b, target) {
+		return true, 0
+	}
+	for i, m := range g.Moves {
+		if err := b.Apply(m); err != nil {
+			return false, 0
+		}
+		if samePosition(b, target) {
+			return true, i + 1
+		}
+	}
+	return false, 0
+}
+
+// samePosition reports whether a and b are the same position for
+// repetition purposes, ignoring the move counters.
+func samePosition(a, b *Board) bool {
+	if a.squares != b.squares || a.turn != b.turn || a.castling != b.castling {
+		return false
+	}
+	aEP, bEP := a.epCapturable(), b.epCapturable()
+	return aEP == bEP && (!aEP || a.epTarget == b.epTarget)
+}
+
+// GameRef identifies a position reached in a game added to a PositionIndex.
+type GameRef struct {
+	// Index is the position of the game in the order it was added to the
+	// index, starting at zero.
+	Index int
+	// Game is the game itself.
+	Game *Game
+	// Ply is the number of mainline plies played when the position was
+	// first reached; 0 is the game's initial position.
+	Ply int
+}
+
+// positionEntry records the first ply at which a game reached a position.
+type positionEntry struct {
+	game int32
+	ply  int32
+}
+
+// PositionIndex finds the games of a collection that reached a given
+// position, regardless of the move order that led there. It stores only
+// position hashes (see Board.Hash), not boards or FENs, so a large database
+// can be indexed in memory. The zero value is an empty index ready to use.
+type PositionIndex struct {
+	games     []*Game
+	positions map[uint64][]positionEntry
+}
+
+// Add replays the mainline of game and records every position it reaches,
+// including its initial position. A position repeated within the game is
+// recorded once, at its first occurrence. If the mainline contains an
+// illegal move, the positions before it are still recorded and a
+// *ReplayError is returned; the game is added either way.
+func (x *PositionIndex) Add(game *Game) error {
+	if x.positions == nil {
+		x.positions = make(map[uint64][]positionEntry)
+	}
+	id := int32(len(x.games))
+	x.games = append(x.games, game)
+
+	b, err := game.InitialBoard()
+	if err != nil {
+		return err
+	}
+	record := func(ply int) {
+		h := b.Hash()
+		entries := x.positions[h]
+		if n := len(entries); n > 0 && entries[n-1].game == id {
+			return
+		}
+		x.positions[h] = append(entries, positionEntry{game: id, ply: int32(ply)})
+	}
+	record(0)
+	for i, m := range game.Moves {
+		if err := b.Apply(m); err != nil {
+			return newReplayError(b, i+1, m, err)
+		}
+		record(i + 1)
+	}
+	return nil
+}
+
+// Len returns the number of games added to the index.
+func (x *PositionIndex) Len() int {
+	return len(x.games)
+}
+
+// Find returns the games that reached the position given in Forsyth-Edwards
+// Notation, in the order they were added. The FEN's move counters are
+// ignored. Find returns nil if no game reached the position or fen is not
+// valid.
+func (x *PositionIndex) Find(fen string) []GameRef {
+	b, err := ParseFEN(fen)
+	if err != nil {
+		return nil
+	}
+	return x.FindBoard(b)
+}
+
+// FindBoard is like Find, but takes the position as a Board.
+func (x *PositionIndex) FindBoard(b *Board) []GameRef {
+	entries := x.positions[b.Hash()]
+	if len(entries) == 0 {
+		return nil
+	}
+	refs := make([]GameRef, len(entries))
+	for i, e := range entries {
+		refs[i] = GameRef{Index: int(e.game), Game: x.games[e.game], Ply: int(e.ply)}
+	}
+	return refs
+}
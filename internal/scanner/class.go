@@ -0,0 +1,56 @@
+package scanner
+
+import "github.com/YashBhalodi/chessnote/internal/util"
+
+// Character classes of the ASCII runes, looked up in runeClasses by the
+// scanner's per-rune loops instead of chaining comparisons.
+const (
+	classWhitespace uint8 = 1 << iota // Space, tab, newline or carriage return
+	classLetter                       // 'a'-'z' or 'A'-'Z'
+	classDigit                        // '0'-'9'
+	classIdent                        // Any rune that can continue an identifier
+)
+
+// runeClasses holds the classes of each ASCII rune, as defined by the util
+// predicates.
+var runeClasses = func() (t [128]uint8) {
+	for r := rune(0); r < 128; r++ {
+		if util.IsWhitespace(r) {
+			t[r] |= classWhitespace
+		}
+		if util.IsLetter(r) {
+			t[r] |= classLetter | classIdent
+		}
+		if util.IsDigit(r) {
+			t[r] |= classDigit | classIdent
+		}
+	}
+	for _, r := range "_+#x=-/" {
+		t[r] |= classIdent
+	}
+	return t
+}()
+
+// isWhitespace is util.IsWhitespace, backed by runeClasses.
+func isWhitespace(r rune) bool {
+	return r >= 0 && r < 128 && runeClasses[r]&classWhitespace != 0
+}
+
+// isDigit is util.IsDigit, backed by runeClasses.
+func isDigit(r rune) bool {
+	return r >= 0 && r < 128 && runeClasses[r]&classDigit != 0
+}
+
+// isIdentStart reports whether r starts an identifier: a letter or a digit.
+func isIdentStart(r rune) bool {
+	return r >= 0 && r < 128 && runeClasses[r]&(classLetter|classDigit) != 0
+}
+
+// isIdentPart reports whether r can continue an identifier, such as a move,
+// a tag name or a result.
+func isIdentPart(r rune) bool {
+	if r >= 0 && r < 128 {
+		return runeClasses[r]&classIdent != 0
+	}
+	return r == '½'
+}
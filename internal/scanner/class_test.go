@@ -0,0 +1,67 @@
+package scanner
+
+import (
+	"testing"
+
+	"github.com/YashBhalodi/chessnote/internal/util"
+)
+
+func TestRuneClassesMatchUtil(t *testing.T) {
+	t.Parallel()
+	for r := rune(-1); r < 256; r++ {
+		if got, want := isWhitespace(r), util.IsWhitespace(r); got != want {
+			t.Errorf("isWhitespace(%q) = %v, want %v", r, got, want)
+		}
+		if got, want := isDigit(r), util.IsDigit(r); got != want {
+			t.Errorf("isDigit(%q) = %v, want %v", r, got, want)
+		}
+		if got, want := isIdentStart(r), util.IsLetter(r) || util.IsDigit(r); got != want {
+			t.Errorf("isIdentStart(%q) = %v, want %v", r, got, want)
+		}
+	}
+
+	for _, r := range "aZ09_+#x=-/½" {
+		if !isIdentPart(r) {
+			t.Errorf("isIdentPart(%q) = false, want true", r)
+		}
+	}
+	for _, r := range " .[]{}()$!?*\"é" {
+		if isIdentPart(r) {
+			t.Errorf("isIdentPart(%q) = true, want false", r)
+		}
+	}
+}
+
+// classifyInput is typical movetext for the rune classification benchmarks.
+var classifyInput = []rune(`1. e4 {Best by test} e5 2. Nf3 Nc6 3. Bb5 a6 (3... Nf6 4. O-O) 4. Ba4 Nf6 5. O-O Be7 6. Re1 b5 7. Bb3 d6 8. c3 O-O 9. h3 $1 Na5 10. Bc2 c5 11. d4 Qc7 12. Nbd2 cxd4 13. cxd4 Nc6 14. Nb3 a5 15. Be3 a4 16. Nbd2 Bd7 17. Rc1 Qb7 18. Qe2 Rfe8 19. Bd3 exd4 20. Nxd4 Nxd4 21. Bxd4 Bf8 22. Qf3 Bc6 1/2-1/2`)
+
+// BenchmarkClassifyChained classifies runes with the chained comparisons
+// the scanner used before runeClasses.
+func BenchmarkClassifyChained(b *testing.B) {
+	n := 0
+	for i := 0; i < b.N; i++ {
+		for _, r := range classifyInput {
+			if util.IsWhitespace(r) {
+				n++
+			} else if util.IsLetter(r) || util.IsDigit(r) || r == '½' || r == '_' || r == '+' || r == '#' || r == 'x' || r == '=' || r == '-' || r == '/' {
+				n += 2
+			}
+		}
+	}
+	_ = n
+}
+
+// BenchmarkClassifyTable classifies the same runes with runeClasses.
+func BenchmarkClassifyTable(b *testing.B) {
+	n := 0
+	for i := 0; i < b.N; i++ {
+		for _, r := range classifyInput {
+			if isWhitespace(r) {
+				n++
+			} else if isIdentPart(r) {
+				n += 2
+			}
+		}
+	}
+	_ = n
+}
@@ -3,29 +3,126 @@ package scanner
 import (
 	"bufio"
 	"io"
-	"strconv"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/YashBhalodi/chessnote/internal/util"
 )
 
 // Scanner is responsible for lexical analysis of a PGN input stream.
 type Scanner struct {
-	r *bufio.Reader
+	r                  *bufio.Reader // Nil when reading from src.
+	src                []byte        // The input of a scanner created by NewScannerFromBytes.
+	off                int           // The offset of the next rune in src.
+	prevOff            int           // The value of off before the last read.
+	preserveWhitespace bool
+	lazyComments       bool   // Whether COMMENT tokens from src are left empty.
+	atLineStart        bool   // Whether the next rune starts a line.
+	prevAtLineStart    bool   // The value of atLineStart before the last read.
+	afterCR            bool   // Whether the last rune read was '\r'.
+	prevAfterCR        bool   // The value of afterCR before the last read.
+	pending            *Token // A token split off the previous one, returned next.
+	pendingPos         Position
+	pos                Position // The position of the next rune.
+	prevPos            Position // The value of pos before the last read.
+	tokPos             Position // The position of the last token returned by Scan.
+	commentStart       int      // The offset in src of the last comment's text.
+	commentEnd         int      // The offset in src just past the last comment's text.
 }
 
 // NewScanner returns a new instance of Scanner.
 func NewScanner(r io.Reader) *Scanner {
-	return &Scanner{r: bufio.NewReader(r)}
+	return &Scanner{r: bufio.NewReader(r), atLineStart: true, pos: Position{Line: 1, Column: 1}}
 }
 
-// Scan returns the next PGN token and its literal value.
+// NewScannerFromBytes returns a Scanner that reads directly from b instead
+// of through a bufio.Reader, avoiding a method call per rune for input that
+// is already in memory. It produces the same tokens as NewScanner over the
+// same bytes. The scanner does not modify b, which must not change while it
+// is in use.
+func NewScannerFromBytes(b []byte) *Scanner {
+	return &Scanner{src: b, atLineStart: true, pos: Position{Line: 1, Column: 1}}
+}
+
+// Reset discards any buffered input and makes the scanner read from r, as if
+// it had just been created by NewScanner. The read buffer is reused if the
+// scanner already has one.
+func (s *Scanner) Reset(r io.Reader) {
+	if s.r == nil {
+		s.r = bufio.NewReader(r)
+	} else {
+		s.r.Reset(r)
+	}
+	s.src, s.off, s.prevOff = nil, 0, 0
+	s.commentStart, s.commentEnd = 0, 0
+	s.atLineStart = true
+	s.prevAtLineStart = false
+	s.afterCR, s.prevAfterCR = false, false
+	s.pending = nil
+	s.pos, s.prevPos, s.tokPos = Position{Line: 1, Column: 1}, Position{}, Position{}
+}
+
+// Pos returns the position of the first character of the token most
+// recently returned by Scan or ScanTagValue. For EOF, it is the position
+// just past the end of the input.
+func (s *Scanner) Pos() Position {
+	return s.tokPos
+}
+
+// SetPreserveWhitespace controls whether Scan returns WHITESPACE tokens. By
+// default whitespace only separates tokens and is discarded. When preserve
+// is true, every run of whitespace between tokens is returned as a single
+// WHITESPACE token carrying the exact run, so tools that rewrite PGN can
+// keep or normalize the original spacing. ScanTagValue always skips the
+// whitespace before a tag value.
+func (s *Scanner) SetPreserveWhitespace(preserve bool) {
+	s.preserveWhitespace = preserve
+}
+
+// SetLazyComments controls whether comment text is copied into COMMENT
+// tokens. When lazy is true and the scanner reads from a byte slice, COMMENT
+// tokens carry an empty Literal and CommentSpan reports where their text
+// lies in the input instead, which saves an allocation per comment. It has
+// no effect on a scanner created by NewScanner.
+func (s *Scanner) SetLazyComments(lazy bool) {
+	s.lazyComments = lazy
+}
+
+// CommentSpan returns the byte offsets in the input of the text of the
+// COMMENT token most recently returned by Scan, excluding its delimiters,
+// when lazy comments are enabled. See SetLazyComments.
+func (s *Scanner) CommentSpan() (start, end int) {
+	return s.commentStart, s.commentEnd
+}
+
+// Source returns the input of a scanner created by NewScannerFromBytes, or
+// nil for a scanner that reads from an io.Reader.
+func (s *Scanner) Source() []byte {
+	return s.src
+}
+
+// Scan returns the next PGN token and its literal value. A line whose first
+// character is '%' is an escape line, as defined by the PGN standard, and is
+// skipped entirely.
 func (s *Scanner) Scan() Token {
+	if s.pending != nil {
+		tok := *s.pending
+		s.pending = nil
+		s.tokPos = s.pendingPos
+		return tok
+	}
+	s.tokPos = s.pos
+	lineStart := s.atLineStart
 	r := s.read()
+	if r == '%' && lineStart {
+		s.skipLine()
+		return s.Scan()
+	}
 
-	if util.IsWhitespace(r) {
+	if isWhitespace(r) {
 		s.unread()
 		return s.scanWhitespace()
-	} else if util.IsLetter(r) || util.IsDigit(r) {
+	} else if isIdentStart(r) {
 		s.unread()
 		return s.scanIdent()
 	}
@@ -45,6 +142,13 @@ func (s *Scanner) Scan() Token {
 		return s.scanString()
 	case '.':
 		return Token{Type: DOT, Literal: string(r)}
+	case '½':
+		// A draw written as "½-½".
+		s.unread()
+		return s.scanIdent()
+	case '…':
+		// Some editors replace "..." with a single ellipsis character.
+		return Token{Type: DOT, Literal: string(r)}
 	case '*':
 		return Token{Type: ASTERISK, Literal: string(r)}
 	case '{':
@@ -53,23 +157,71 @@ func (s *Scanner) Scan() Token {
 		return s.scanCommentLine()
 	case '$':
 		return s.scanNAG()
+	case '!', '?':
+		s.unread()
+		return s.scanGlyph()
 	}
 
 	return Token{Type: ILLEGAL, Literal: string(r)}
 }
 
+// ScanTagValue scans a tag value leniently, for PGN exporters that do not
+// quote values correctly. In addition to a double-quoted string, it accepts
+// a single-quoted string or a bare value running up to the closing ']',
+// which is left unconsumed. The value is always returned as a STRING token.
+func (s *Scanner) ScanTagValue() Token {
+	s.tokPos = s.pos
+	r := s.read()
+	for isWhitespace(r) {
+		s.tokPos = s.pos
+		r = s.read()
+	}
+
+	switch r {
+	case '"':
+		return s.scanString()
+	case '\'':
+		var lit string
+		for {
+			r := s.read()
+			if r == '\'' || r == eof {
+				break
+			}
+			lit += string(r)
+		}
+		return Token{Type: STRING, Literal: lit}
+	}
+
+	s.unread()
+	var lit string
+	for {
+		r := s.read()
+		if r == eof {
+			break
+		} else if r == ']' {
+			s.unread()
+			break
+		}
+		lit += string(r)
+	}
+	return Token{Type: STRING, Literal: strings.TrimRightFunc(lit, isWhitespace)}
+}
+
 func (s *Scanner) scanWhitespace() Token {
 	var lit string
 	for {
 		r := s.read()
 		if r == eof {
 			break
-		} else if !util.IsWhitespace(r) {
+		} else if !isWhitespace(r) {
 			s.unread()
 			break
 		}
 		lit += string(r)
 	}
+	if s.preserveWhitespace {
+		return Token{Type: WHITESPACE, Literal: lit}
+	}
 	// Whitespace is not a token, so we recursively call Scan to get the next one.
 	return s.Scan()
 }
@@ -80,7 +232,7 @@ func (s *Scanner) scanIdent() Token {
 		r := s.read()
 		if r == eof {
 			break
-		} else if !util.IsLetter(r) && !util.IsDigit(r) && r != '_' && r != '+' && r != '#' && r != 'x' && r != '=' && r != '-' && r != '/' {
+		} else if !isIdentPart(r) {
 			s.unread()
 			break
 		}
@@ -91,15 +243,54 @@ func (s *Scanner) scanIdent() Token {
 	// But for PGN, numbers only appear as move numbers or in tags, where they
 	// can be treated as identifiers. We only really need to distinguish them
 	// to know when we are in the movetext section.
-	if len(lit) > 0 && util.IsDigit(rune(lit[0])) {
-		if _, err := strconv.Atoi(lit); err == nil {
-			// It's a pure number.
-			return Token{Type: NUMBER, Literal: lit}
-		}
+	// Only the digits are checked, not the value, so a number too large
+	// for an int is still a NUMBER and costs no conversion.
+	if len(lit) > 0 && allDigits(lit) {
+		return Token{Type: NUMBER, Literal: lit}
+	}
+	if move, result, ok := splitGluedResult(lit); ok {
+		s.pending = &Token{Type: IDENT, Literal: result}
+		s.pendingPos = Position{Line: s.tokPos.Line, Column: s.tokPos.Column + utf8.RuneCountInString(move)}
+		return Token{Type: IDENT, Literal: move}
 	}
 	return Token{Type: IDENT, Literal: lit}
 }
 
+// allDigits reports whether lit consists only of ASCII digits.
+func allDigits(lit string) bool {
+	for i := 0; i < len(lit); i++ {
+		if !isDigit(rune(lit[i])) {
+			return false
+		}
+	}
+	return true
+}
+
+// gluedResults are the result tokens that splitGluedResult separates from a
+// preceding move.
+var gluedResults = [...]string{"1-0", "0-1", "1/2-1/2"}
+
+// splitGluedResult splits an identifier such as "Qxf7#1-0", written by
+// exporters that omit the space before the result, into the move and the
+// result. It only splits when the move part starts with a letter and ends
+// the way a move can end, with a rank digit, a check or mate symbol, a
+// promotion piece or the "O" of castling, so "Ra1-0" is left alone.
+func splitGluedResult(lit string) (move, result string, ok bool) {
+	for _, result := range gluedResults {
+		move := strings.TrimSuffix(lit, result)
+		if move == lit || move == "" || !util.IsLetter(rune(move[0])) {
+			continue
+		}
+		if strings.ContainsRune("12345678+#QRBNO", rune(move[len(move)-1])) {
+			return move, result, true
+		}
+	}
+	return "", "", false
+}
+
+// scanString scans a double-quoted string, whose opening quote has been
+// read. As in the PGN standard, a backslash escapes a quote or another
+// backslash; any other backslash is kept as it is.
 func (s *Scanner) scanString() Token {
 	var lit string
 	for {
@@ -107,12 +298,29 @@ func (s *Scanner) scanString() Token {
 		if r == '"' || r == eof {
 			break
 		}
+		if r == '\\' {
+			if next := s.read(); next == '"' || next == '\\' {
+				r = next
+			} else {
+				s.unread()
+			}
+		}
 		lit += string(r)
 	}
 	return Token{Type: STRING, Literal: lit}
 }
 
 func (s *Scanner) scanCommentBlock() Token {
+	if s.lazyComments && s.r == nil {
+		s.commentStart = s.off
+		for {
+			if r := s.read(); r == '}' || r == eof {
+				break
+			}
+		}
+		s.commentEnd = s.prevOff
+		return Token{Type: COMMENT}
+	}
 	var lit string
 	for {
 		r := s.read()
@@ -125,10 +333,20 @@ func (s *Scanner) scanCommentBlock() Token {
 }
 
 func (s *Scanner) scanCommentLine() Token {
+	if s.lazyComments && s.r == nil {
+		s.commentStart = s.off
+		for {
+			if r := s.read(); isLineEnd(r) || r == eof {
+				break
+			}
+		}
+		s.commentEnd = s.prevOff
+		return Token{Type: COMMENT}
+	}
 	var lit string
 	for {
 		r := s.read()
-		if r == '\n' || r == eof {
+		if isLineEnd(r) || r == eof {
 			break
 		}
 		lit += string(r)
@@ -140,7 +358,7 @@ func (s *Scanner) scanNAG() Token {
 	var lit string
 	for {
 		r := s.read()
-		if !util.IsDigit(r) {
+		if !isDigit(r) {
 			s.unread()
 			break
 		}
@@ -149,16 +367,86 @@ func (s *Scanner) scanNAG() Token {
 	return Token{Type: NAG, Literal: lit}
 }
 
+func (s *Scanner) scanGlyph() Token {
+	var lit string
+	for {
+		r := s.read()
+		if r != '!' && r != '?' {
+			s.unread()
+			break
+		}
+		lit += string(r)
+	}
+	return Token{Type: GLYPH, Literal: lit}
+}
+
+// skipLine discards the rest of the current line, including its newline.
+func (s *Scanner) skipLine() {
+	for {
+		r := s.read()
+		if isLineEnd(r) || r == eof {
+			return
+		}
+	}
+}
+
+// isLineEnd reports whether r ends a line. Lines end with "\n", "\r\n" or,
+// in files from classic Mac OS, a lone "\r".
+func isLineEnd(r rune) bool {
+	return r == '\n' || r == '\r'
+}
+
 func (s *Scanner) read() rune {
-	r, _, err := s.r.ReadRune()
-	if err != nil {
-		return eof
+	s.prevAtLineStart = s.atLineStart
+	s.prevAfterCR = s.afterCR
+	var r rune
+	if s.r == nil {
+		s.prevOff = s.off
+		if s.off >= len(s.src) {
+			return eof
+		}
+		r = rune(s.src[s.off])
+		size := 1
+		if r >= utf8.RuneSelf {
+			r, size = utf8.DecodeRune(s.src[s.off:])
+		}
+		s.off += size
+	} else {
+		var err error
+		r, _, err = s.r.ReadRune()
+		if err != nil {
+			return eof
+		}
+	}
+	s.atLineStart = isLineEnd(r)
+	s.prevPos = s.pos
+	switch {
+	case r == '\n' && s.afterCR:
+		// The line break of a "\r\n" pair was counted at the '\r'.
+	case isLineEnd(r):
+		s.pos = Position{Line: s.pos.Line + 1, Column: 1}
+	default:
+		s.pos.Column++
 	}
+	s.afterCR = r == '\r'
 	return r
 }
 
 func (s *Scanner) unread() {
-	_ = s.r.UnreadRune()
+	if s.r == nil {
+		if s.off != s.prevOff {
+			s.off = s.prevOff
+			s.atLineStart = s.prevAtLineStart
+			s.afterCR = s.prevAfterCR
+			s.pos = s.prevPos
+		}
+		return
+	}
+	if s.r.UnreadRune() == nil {
+		s.atLineStart = s.prevAtLineStart
+		s.afterCR = s.prevAfterCR
+		s.pos = s.prevPos
+	}
 }
 
 var eof = rune(0)
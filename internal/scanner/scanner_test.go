@@ -1,6 +1,7 @@
 package scanner
 
 import (
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -24,6 +25,28 @@ func TestScanner(t *testing.T) {
 				{Type: EOF},
 			},
 		},
+		{
+			name:  "multi-byte tag value",
+			input: `[White "Réti, Richard"]`,
+			want: []Token{
+				{Type: LBRACKET, Literal: "["},
+				{Type: IDENT, Literal: "White"},
+				{Type: STRING, Literal: "Réti, Richard"},
+				{Type: RBRACKET, Literal: "]"},
+				{Type: EOF},
+			},
+		},
+		{
+			name:  "escaped tag value",
+			input: `[Event "The \"Immortal\" \\ C:\games"]`,
+			want: []Token{
+				{Type: LBRACKET, Literal: "["},
+				{Type: IDENT, Literal: "Event"},
+				{Type: STRING, Literal: `The "Immortal" \ C:\games`},
+				{Type: RBRACKET, Literal: "]"},
+				{Type: EOF},
+			},
+		},
 		{
 			name:  "simple move",
 			input: `1. e4`,
@@ -34,6 +57,16 @@ func TestScanner(t *testing.T) {
 				{Type: EOF},
 			},
 		},
+		{
+			name:  "number too large for an int",
+			input: `123456789012345678901234567890. e4`,
+			want: []Token{
+				{Type: NUMBER, Literal: "123456789012345678901234567890"},
+				{Type: DOT, Literal: "."},
+				{Type: IDENT, Literal: "e4"},
+				{Type: EOF},
+			},
+		},
 		{
 			name:  "disambiguated move",
 			input: `Rdf8`,
@@ -90,6 +123,65 @@ func TestScanner(t *testing.T) {
 				{Type: EOF},
 			},
 		},
+		{
+			name:  "move with glyphs",
+			input: `Qh5?? $10 e5!?`,
+			want: []Token{
+				{Type: IDENT, Literal: "Qh5"},
+				{Type: GLYPH, Literal: "??"},
+				{Type: NAG, Literal: "10"},
+				{Type: IDENT, Literal: "e5"},
+				{Type: GLYPH, Literal: "!?"},
+				{Type: EOF},
+			},
+		},
+		{
+			name:  "glyphs after check and castling",
+			input: `Qh4#!! O-O?`,
+			want: []Token{
+				{Type: IDENT, Literal: "Qh4#"},
+				{Type: GLYPH, Literal: "!!"},
+				{Type: IDENT, Literal: "O-O"},
+				{Type: GLYPH, Literal: "?"},
+				{Type: EOF},
+			},
+		},
+		{
+			name:  "result glued to a mating move",
+			input: `Qxf7#1-0`,
+			want: []Token{
+				{Type: IDENT, Literal: "Qxf7#"},
+				{Type: IDENT, Literal: "1-0"},
+				{Type: EOF},
+			},
+		},
+		{
+			name:  "draw glued to a move",
+			input: `Kf2 1/2-1/2 Kd81/2-1/2`,
+			want: []Token{
+				{Type: IDENT, Literal: "Kf2"},
+				{Type: IDENT, Literal: "1/2-1/2"},
+				{Type: IDENT, Literal: "Kd8"},
+				{Type: IDENT, Literal: "1/2-1/2"},
+				{Type: EOF},
+			},
+		},
+		{
+			name:  "move that only looks glued",
+			input: `Ra1-0`,
+			want: []Token{
+				{Type: IDENT, Literal: "Ra1-0"},
+				{Type: EOF},
+			},
+		},
+		{
+			name:  "multi-line comment",
+			input: "{First line\n  second line}",
+			want: []Token{
+				{Type: COMMENT, Literal: "First line\n  second line"},
+				{Type: EOF},
+			},
+		},
 		{
 			name:  "draw result",
 			input: `1/2-1/2`,
@@ -102,16 +194,347 @@ func TestScanner(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			s := NewScanner(strings.NewReader(tc.input))
-			for i, wantToken := range tc.want {
-				gotToken := s.Scan()
-				if gotToken.Type != wantToken.Type {
-					t.Fatalf("test %d: token type wrong. got=%v, want=%v", i, gotToken.Type, wantToken.Type)
+			forEachScanner(t, tc.input, func(t *testing.T, s *Scanner) {
+				for i, wantToken := range tc.want {
+					gotToken := s.Scan()
+					if gotToken.Type != wantToken.Type {
+						t.Fatalf("test %d: token type wrong. got=%v, want=%v", i, gotToken.Type, wantToken.Type)
+					}
+					if gotToken.Literal != wantToken.Literal {
+						t.Fatalf("test %d: token literal wrong. got=%q, want=%q", i, gotToken.Literal, wantToken.Literal)
+					}
 				}
-				if gotToken.Literal != wantToken.Literal {
-					t.Fatalf("test %d: token literal wrong. got=%q, want=%q", i, gotToken.Literal, wantToken.Literal)
+			})
+		})
+	}
+}
+
+func TestScanTagValue(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"double quoted", ` "Foo Bar"]`, "Foo Bar"},
+		{"single quoted", ` 'Foo Bar']`, "Foo Bar"},
+		{"bare number", ` 3]`, "3"},
+		{"bare words with trailing space", ` Foo Bar  ]`, "Foo Bar"},
+		{"empty bare value", ` ]`, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			forEachScanner(t, tc.input, func(t *testing.T, s *Scanner) {
+				got := s.ScanTagValue()
+				if got.Type != STRING || got.Literal != tc.want {
+					t.Fatalf("ScanTagValue() = %v, want STRING %q", got, tc.want)
 				}
+				if next := s.Scan(); next.Type != RBRACKET {
+					t.Fatalf("expected ']' after the value, got %v", next)
+				}
+			})
+		})
+	}
+}
+
+func TestScannerEllipsis(t *testing.T) {
+	t.Parallel()
+	input := "12... Nf6 13… Nc3"
+	want := []Token{
+		{Type: NUMBER, Literal: "12"},
+		{Type: DOT, Literal: "."},
+		{Type: DOT, Literal: "."},
+		{Type: DOT, Literal: "."},
+		{Type: IDENT, Literal: "Nf6"},
+		{Type: NUMBER, Literal: "13"},
+		{Type: DOT, Literal: "…"},
+		{Type: IDENT, Literal: "Nc3"},
+		{Type: EOF},
+	}
+	forEachScanner(t, input, func(t *testing.T, s *Scanner) {
+		for i, wantToken := range want {
+			if got := s.Scan(); got != wantToken {
+				t.Fatalf("token %d: got %v, want %v", i, got, wantToken)
+			}
+		}
+	})
+}
+
+func TestScannerUnicodeDraw(t *testing.T) {
+	t.Parallel()
+	want := []Token{
+		{Type: IDENT, Literal: "e5"},
+		{Type: IDENT, Literal: "½-½"},
+		{Type: EOF},
+	}
+	forEachScanner(t, "e5 ½-½", func(t *testing.T, s *Scanner) {
+		for i, wantToken := range want {
+			if got := s.Scan(); got != wantToken {
+				t.Fatalf("token %d: got %v, want %v", i, got, wantToken)
+			}
+		}
+	})
+}
+
+func TestScannerPreserveWhitespace(t *testing.T) {
+	t.Parallel()
+	input := "[Event \"Test\"]\r\n\r\n1.  e4\te5 {Solid}\n*"
+	want := []Token{
+		{Type: LBRACKET, Literal: "["},
+		{Type: IDENT, Literal: "Event"},
+		{Type: WHITESPACE, Literal: " "},
+		{Type: STRING, Literal: "Test"},
+		{Type: RBRACKET, Literal: "]"},
+		{Type: WHITESPACE, Literal: "\r\n\r\n"},
+		{Type: NUMBER, Literal: "1"},
+		{Type: DOT, Literal: "."},
+		{Type: WHITESPACE, Literal: "  "},
+		{Type: IDENT, Literal: "e4"},
+		{Type: WHITESPACE, Literal: "\t"},
+		{Type: IDENT, Literal: "e5"},
+		{Type: WHITESPACE, Literal: " "},
+		{Type: COMMENT, Literal: "Solid"},
+		{Type: WHITESPACE, Literal: "\n"},
+		{Type: ASTERISK, Literal: "*"},
+		{Type: EOF},
+	}
+
+	forEachScanner(t, input, func(t *testing.T, s *Scanner) {
+		s.SetPreserveWhitespace(true)
+		for i, wantToken := range want {
+			if got := s.Scan(); got != wantToken {
+				t.Fatalf("token %d: got %v, want %v", i, got, wantToken)
 			}
+		}
+	})
+
+	// Without the flag, the same input yields no WHITESPACE tokens.
+	s := NewScanner(strings.NewReader(input))
+	for tok := s.Scan(); tok.Type != EOF; tok = s.Scan() {
+		if tok.Type == WHITESPACE {
+			t.Fatalf("got a WHITESPACE token with whitespace preservation disabled")
+		}
+	}
+}
+
+func TestScannerEscapeLines(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name  string
+		input string
+		want  []Token
+	}{
+		{
+			name:  "escape line between moves",
+			input: "1. e4\n% some data\ne5",
+			want: []Token{
+				{Type: NUMBER, Literal: "1"},
+				{Type: DOT, Literal: "."},
+				{Type: IDENT, Literal: "e4"},
+				{Type: IDENT, Literal: "e5"},
+				{Type: EOF},
+			},
+		},
+		{
+			name:  "escape line at the start of the input",
+			input: "%header\r\n[Event \"Test\"]",
+			want: []Token{
+				{Type: LBRACKET, Literal: "["},
+				{Type: IDENT, Literal: "Event"},
+				{Type: STRING, Literal: "Test"},
+				{Type: RBRACKET, Literal: "]"},
+				{Type: EOF},
+			},
+		},
+		{
+			name:  "escape line at the end of the input",
+			input: "*\n%trailer",
+			want: []Token{
+				{Type: ASTERISK, Literal: "*"},
+				{Type: EOF},
+			},
+		},
+		{
+			name:  "percent sign after the line start",
+			input: "e4 %",
+			want: []Token{
+				{Type: IDENT, Literal: "e4"},
+				{Type: ILLEGAL, Literal: "%"},
+			},
+		},
+		{
+			name:  "percent sign inside a comment",
+			input: "{\n%kept}",
+			want: []Token{
+				{Type: COMMENT, Literal: "\n%kept"},
+				{Type: EOF},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			forEachScanner(t, tc.input, func(t *testing.T, s *Scanner) {
+				for i, wantToken := range tc.want {
+					if got := s.Scan(); got != wantToken {
+						t.Fatalf("token %d: got %v, want %v", i, got, wantToken)
+					}
+				}
+			})
 		})
 	}
 }
+
+func TestScannerReset(t *testing.T) {
+	t.Parallel()
+	s := NewScanner(strings.NewReader("e4 e5 Nf3"))
+	if got := s.Scan(); got != (Token{Type: IDENT, Literal: "e4"}) {
+		t.Fatalf("got %v, want e4", got)
+	}
+
+	// The unread input is discarded, and the new input starts a line, so a
+	// leading escape line is still skipped.
+	s.Reset(strings.NewReader("%escape\nd4"))
+	want := []Token{{Type: IDENT, Literal: "d4"}, {Type: EOF}}
+	for i, wantToken := range want {
+		if got := s.Scan(); got != wantToken {
+			t.Fatalf("token %d after Reset: got %v, want %v", i, got, wantToken)
+		}
+	}
+}
+
+// forEachScanner runs fn as a subtest with a reader-based scanner and a
+// byte-slice scanner over the same input, so both are held to the same
+// expectations.
+func forEachScanner(t *testing.T, input string, fn func(t *testing.T, s *Scanner)) {
+	t.Helper()
+	t.Run("reader", func(t *testing.T) {
+		fn(t, NewScanner(strings.NewReader(input)))
+	})
+	t.Run("bytes", func(t *testing.T) {
+		fn(t, NewScannerFromBytes([]byte(input)))
+	})
+}
+
+func TestScannerFromBytesMatchesReader(t *testing.T) {
+	t.Parallel()
+	inputs := []string{
+		"[Event \"Caf\u00e9 \u2654\"]\n\n1. e4 {\u00e9chec} e5!? 2. Qh5 Nc6 $1 (2... g6) 3. Qxf7#1-0",
+		"%escape\r\n1. d4 ; rest of line\n d5 *\n%trailer",
+		"\xff\xfe invalid bytes",
+	}
+	for _, input := range inputs {
+		fromReader := NewScanner(strings.NewReader(input))
+		fromBytes := NewScannerFromBytes([]byte(input))
+		for i := 0; ; i++ {
+			want, got := fromReader.Scan(), fromBytes.Scan()
+			if got != want {
+				t.Fatalf("%q: token %d: got %v from bytes, want %v", input, i, got, want)
+			}
+			if want.Type == EOF || want.Type == ILLEGAL {
+				break
+			}
+		}
+	}
+}
+
+func TestScannerPos(t *testing.T) {
+	t.Parallel()
+	input := "[Event \"Café\"]\r\n%escape\n  1. e4 {a\nb} Qxf7#1-0"
+	want := []struct {
+		tok Token
+		pos Position
+	}{
+		{Token{Type: LBRACKET, Literal: "["}, Position{Line: 1, Column: 1}},
+		{Token{Type: IDENT, Literal: "Event"}, Position{Line: 1, Column: 2}},
+		{Token{Type: STRING, Literal: "Café"}, Position{Line: 1, Column: 8}},
+		{Token{Type: RBRACKET, Literal: "]"}, Position{Line: 1, Column: 14}},
+		{Token{Type: NUMBER, Literal: "1"}, Position{Line: 3, Column: 3}},
+		{Token{Type: DOT, Literal: "."}, Position{Line: 3, Column: 4}},
+		{Token{Type: IDENT, Literal: "e4"}, Position{Line: 3, Column: 6}},
+		{Token{Type: COMMENT, Literal: "a\nb"}, Position{Line: 3, Column: 9}},
+		{Token{Type: IDENT, Literal: "Qxf7#"}, Position{Line: 4, Column: 4}},
+		{Token{Type: IDENT, Literal: "1-0"}, Position{Line: 4, Column: 9}},
+		{Token{Type: EOF}, Position{Line: 4, Column: 12}},
+	}
+	forEachScanner(t, input, func(t *testing.T, s *Scanner) {
+		for i, w := range want {
+			if got := s.Scan(); got != w.tok {
+				t.Fatalf("token %d: got %v, want %v", i, got, w.tok)
+			}
+			if got := s.Pos(); got != w.pos {
+				t.Errorf("token %d (%v): Pos() = %+v, want %+v", i, w.tok, got, w.pos)
+			}
+		}
+	})
+}
+
+func TestScannerClassicMacLineEndings(t *testing.T) {
+	t.Parallel()
+	input := "[Event \"Test\"]\r%escape\r1. e4 ; Solid\re5\r\n2. Nf3 *"
+	want := []struct {
+		tok Token
+		pos Position
+	}{
+		{Token{Type: LBRACKET, Literal: "["}, Position{Line: 1, Column: 1}},
+		{Token{Type: IDENT, Literal: "Event"}, Position{Line: 1, Column: 2}},
+		{Token{Type: STRING, Literal: "Test"}, Position{Line: 1, Column: 8}},
+		{Token{Type: RBRACKET, Literal: "]"}, Position{Line: 1, Column: 14}},
+		{Token{Type: NUMBER, Literal: "1"}, Position{Line: 3, Column: 1}},
+		{Token{Type: DOT, Literal: "."}, Position{Line: 3, Column: 2}},
+		{Token{Type: IDENT, Literal: "e4"}, Position{Line: 3, Column: 4}},
+		{Token{Type: COMMENT, Literal: " Solid"}, Position{Line: 3, Column: 7}},
+		{Token{Type: IDENT, Literal: "e5"}, Position{Line: 4, Column: 1}},
+		{Token{Type: NUMBER, Literal: "2"}, Position{Line: 5, Column: 1}},
+		{Token{Type: DOT, Literal: "."}, Position{Line: 5, Column: 2}},
+		{Token{Type: IDENT, Literal: "Nf3"}, Position{Line: 5, Column: 4}},
+		{Token{Type: ASTERISK, Literal: "*"}, Position{Line: 5, Column: 8}},
+		{Token{Type: EOF}, Position{Line: 5, Column: 9}},
+	}
+	forEachScanner(t, input, func(t *testing.T, s *Scanner) {
+		for i, w := range want {
+			if got := s.Scan(); got != w.tok {
+				t.Fatalf("token %d: got %v, want %v", i, got, w.tok)
+			}
+			if got := s.Pos(); got != w.pos {
+				t.Errorf("token %d (%v): Pos() = %+v, want %+v", i, w.tok, got, w.pos)
+			}
+		}
+	})
+}
+
+func TestScannerLazyComments(t *testing.T) {
+	t.Parallel()
+	input := "1. e4 { Good } e5 ; Solid\r\n{unterminated"
+	want := []string{" Good ", " Solid", "unterminated"}
+
+	src := []byte(input)
+	s := NewScannerFromBytes(src)
+	s.SetLazyComments(true)
+	var got []string
+	for tok := s.Scan(); tok.Type != EOF; tok = s.Scan() {
+		if tok.Type != COMMENT {
+			continue
+		}
+		if tok.Literal != "" {
+			t.Errorf("got literal %q, want an empty lazy comment", tok.Literal)
+		}
+		start, end := s.CommentSpan()
+		got = append(got, string(src[start:end]))
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got comment spans %q, want %q", got, want)
+	}
+
+	// A scanner reading from an io.Reader has no source to refer to, so it
+	// keeps copying comments.
+	r := NewScanner(strings.NewReader(input))
+	r.SetLazyComments(true)
+	r.Scan()
+	r.Scan()
+	r.Scan()
+	if tok := r.Scan(); tok != (Token{Type: COMMENT, Literal: " Good "}) {
+		t.Errorf("got %v from a reader, want the comment's literal", tok)
+	}
+}
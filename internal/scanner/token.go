@@ -9,6 +9,13 @@ type Token struct {
 	Literal string
 }
 
+// Position is a location in the scanned input. Line and Column are 1-based,
+// and columns count runes, not bytes.
+type Position struct {
+	Line   int
+	Column int
+}
+
 const (
 	ILLEGAL TokenType = iota // An unknown token
 	EOF                      // End of file
@@ -23,10 +30,13 @@ const (
 	RBRACKET // ]
 	LPAREN   // (
 	RPAREN   // )
-	DOT      // .
+	DOT      // . or the ellipsis character …
 	ASTERISK // *
 
 	// Keywords & Special
 	COMMENT // A comment block or line
 	NAG     // Numeric Annotation Glyph, e.g., $1
+	GLYPH   // Inline annotation glyph, e.g., !, ??, !?
+
+	WHITESPACE // A run of whitespace, only emitted when preserving whitespace
 )
@@ -12,7 +12,7 @@ func IsRank(r rune) bool {
 
 // IsWhitespace checks if a rune is a whitespace character.
 func IsWhitespace(r rune) bool {
-	return r == ' ' || r == '\t' || r == '\n'
+	return r == ' ' || r == '\t' || r == '\n' || r == '\r'
 }
 
 // IsLetter checks if a rune is a letter.
@@ -62,6 +62,7 @@ func TestIsWhitespace(t *testing.T) {
 		{"space", ' ', true},
 		{"tab", '\t', true},
 		{"newline", '\n', true},
+		{"carriage return", '\r', true},
 		{"letter", 'a', false},
 		{"digit", '1', false},
 	}
@@ -0,0 +1,33 @@
+package chessnote
+
+// RedundantDisambiguations returns a reference to every piece move, in the
+// mainline or in a variation, that names its starting file or rank although
+// only one piece of that type could legally reach the destination, such as
+// "Ngf3" when the other knight is pinned. A normalizer can clear the hints
+// of the reported moves without changing their meaning.
+//
+// The game is replayed from its initial position to decide each case. A
+// line is only examined up to its first illegal move, and nothing is
+// reported if the initial position cannot be built.
+func (g *Game) RedundantDisambiguations() []MoveRef {
+	b, err := g.InitialBoard()
+	if err != nil {
+		return nil
+	}
+	var refs []MoveRef
+	replayMoves(b, g.Moves, nil, 1, func(ref MoveRef, before, _ *Board) {
+		m := ref.Move
+		if m.Piece == Pawn || m.IsKingsideCastle || m.IsQueensideCastle {
+			return
+		}
+		if !m.HasFromFile && !m.HasFromRank {
+			return
+		}
+		bare := m
+		bare.From, bare.HasFromFile, bare.HasFromRank = Square{}, false, false
+		if len(before.legalOrigins(bare)) == 1 {
+			refs = append(refs, ref)
+		}
+	})
+	return refs
+}
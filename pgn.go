@@ -2,24 +2,50 @@
 // for parsing Portable Game Notation (PGN), the universal standard for chess game data.
 package chessnote
 
-import "strings"
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"math"
+	"strings"
+)
 
 // SplitMultiGame takes a string containing multiple PGN games and splits them
 // into a slice of individual game strings. It normalizes line endings to
-// handle different file formats (e.g., Windows-style \r\n).
+// handle different file formats: Windows-style \r\n and the lone \r of
+// classic Mac OS files both become \n.
+//
+// A new game starts at each line beginning with an Event tag. Comments
+// between one game's result and the next Event tag stay with the first game,
+// and an Event tag quoted inside a multi-line {...} comment does not start a
+// new game.
 //
 // This utility is useful for pre-processing PGN files that contain an entire
 // database of games before passing each individual game to the parser.
 func SplitMultiGame(pgn string) []string {
-	// Normalize line endings to \n to handle \r\n from Windows files.
+	return SplitMultiGameFunc(pgn, isEventTagLine)
+}
+
+// SplitMultiGameFunc is like SplitMultiGame, but starts a new game at every
+// line for which isBoundary returns true, for databases whose games do not
+// all begin with an Event tag. isBoundary receives each line with
+// surrounding whitespace removed; it is not called for lines that continue a
+// multi-line {...} comment.
+func SplitMultiGameFunc(pgn string, isBoundary func(line string) bool) []string {
+	// Normalize line endings to \n to handle \r\n from Windows files and
+	// \r from classic Mac OS files.
 	pgn = strings.ReplaceAll(pgn, "\r\n", "\n")
+	pgn = strings.ReplaceAll(pgn, "\r", "\n")
 	var games []string
 	var currentGame strings.Builder
 	lines := strings.Split(pgn, "\n")
+	inComment := false
 
 	for _, line := range lines {
 		trimmedLine := strings.TrimSpace(line)
-		if strings.HasPrefix(trimmedLine, "[Event ") && currentGame.Len() > 0 {
+		startsGame := !inComment && isBoundary(trimmedLine)
+		inComment = endsInComment(trimmedLine, inComment)
+		if startsGame && currentGame.Len() > 0 {
 			// Found the start of a new game, so save the previous one.
 			gameStr := strings.TrimSpace(currentGame.String())
 			if gameStr != "" {
@@ -39,3 +65,113 @@ func SplitMultiGame(pgn string) []string {
 
 	return games
 }
+
+// CountGames returns the number of games in a PGN database read from r,
+// such as the total for a progress bar shown while importing it. It finds
+// the boundaries between games as SplitMultiGame does, without parsing any
+// movetext or holding more than a line of input in memory, and agrees with
+// len(SplitMultiGame(pgn)) for the same input. It returns the number of
+// games counted so far and the error if reading from r fails.
+func CountGames(r io.Reader) (int, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), math.MaxInt)
+	sc.Split(scanLines)
+	count := 0
+	inComment := false
+	hasContent := false // Whether the current game has a non-blank line.
+	for sc.Scan() {
+		line := string(bytes.TrimSpace(sc.Bytes()))
+		if !inComment && isEventTagLine(line) {
+			if hasContent {
+				count++
+			}
+			hasContent = false
+		}
+		inComment = endsInComment(line, inComment)
+		if line != "" {
+			hasContent = true
+		}
+	}
+	if hasContent {
+		count++
+	}
+	return count, sc.Err()
+}
+
+// scanLines is a bufio.SplitFunc that splits its input into lines ending
+// with "\n", "\r\n" or a lone "\r", the line endings SplitMultiGame
+// recognizes.
+func scanLines(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	i := bytes.IndexAny(data, "\r\n")
+	switch {
+	case i < 0:
+		if atEOF && len(data) > 0 {
+			return len(data), data, nil
+		}
+		return 0, nil, nil
+	case data[i] == '\n':
+		return i + 1, data[:i], nil
+	case i+1 < len(data):
+		if data[i+1] == '\n' {
+			return i + 2, data[:i], nil
+		}
+		return i + 1, data[:i], nil
+	case atEOF:
+		return i + 1, data[:i], nil
+	}
+	// A '\r' at the end of the buffer may be followed by a '\n'.
+	return 0, nil, nil
+}
+
+// isEventTagLine reports whether line begins with an Event tag, the
+// boundary used by SplitMultiGame.
+func isEventTagLine(line string) bool {
+	return strings.HasPrefix(line, "[Event ")
+}
+
+// endsInComment reports whether a {...} comment is still open at the end of
+// line, given whether one was open at its start. Tag pairs and the rest of a
+// line after a ';' comment cannot open a brace comment, but movetext that
+// follows the tag pairs on the same line, as in `[Result "1-0"] 1. e4 {`,
+// can.
+func endsInComment(line string, inComment bool) bool {
+	if !inComment {
+		line = skipTagPairs(line)
+	}
+	for _, r := range line {
+		switch {
+		case inComment:
+			inComment = r != '}'
+		case r == '{':
+			inComment = true
+		case r == ';':
+			return false
+		}
+	}
+	return inComment
+}
+
+// skipTagPairs returns line without the tag pairs it starts with, such as
+// `[Event "A"] [Result "1-0"]`, or "" if a tag pair is not closed on the
+// line. Brackets and braces inside quoted tag values are skipped.
+func skipTagPairs(line string) string {
+	for strings.HasPrefix(line, "[") {
+		end := -1
+		inString := false
+		for i := 1; i < len(line) && end < 0; i++ {
+			switch c := line[i]; {
+			case inString && c == '\\':
+				i++ // Skip the escaped character.
+			case c == '"':
+				inString = !inString
+			case !inString && c == ']':
+				end = i
+			}
+		}
+		if end < 0 {
+			return ""
+		}
+		line = strings.TrimLeft(line[end+1:], " \t")
+	}
+	return line
+}
@@ -0,0 +1,105 @@
+package chessnote
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// GameReader reads successive games from a stream containing any number of
+// PGN games, such as a database file or os.Stdin. Unlike SplitMultiGame, it
+// does not need the whole input in memory: games are tokenized and parsed
+// incrementally as the data arrives.
+type GameReader struct {
+	r    io.Reader
+	opts []ParserOption
+	p    *Parser
+	err  error
+}
+
+// NewGameReader returns a GameReader that reads games from r. The options
+// are applied to the parser of every game.
+func NewGameReader(r io.Reader, opts ...ParserOption) *GameReader {
+	return &GameReader{r: r, opts: opts}
+}
+
+// Next parses and returns the next game in the stream. It returns io.EOF
+// once the stream is exhausted.
+//
+// Next returns a game as soon as its result has been read, so on a blocking
+// reader such as a pipe each game is yielded while the next one is still
+// arriving, or while the writer waits; it never reads past the result.
+// Comments that follow the result belong to the game but can only be read
+// with what comes after them, so the next call to Next adds them to the
+// previous game's ResultComments before it parses the next game. Once Next
+// returns an error other than io.EOF, the position
+// in the stream is lost and every later call returns the same error.
+func (gr *GameReader) Next() (*Game, error) {
+	if gr.err != nil {
+		return nil, gr.err
+	}
+	if gr.p == nil {
+		gr.p = NewParser(gr.r, gr.opts...)
+		gr.p.streaming = true
+	}
+	if g := gr.p.unfinished; g != nil {
+		gr.p.unfinished = nil
+		gr.p.finishResult(g)
+	}
+	if !gr.p.More() {
+		return nil, io.EOF
+	}
+
+	game, err := gr.p.Parse()
+	if err != nil {
+		gr.err = err
+		return nil, err
+	}
+	// Content after the game is expected here: it is the next game.
+	game.Trailing = false
+	return game, nil
+}
+
+// perGameTags are the tags that ParseWithSharedTags does not copy from the
+// leading tag section, since they describe the first game only.
+var perGameTags = map[string]bool{"Result": true, "PlyCount": true, "FEN": true, "SetUp": true}
+
+// ParseWithSharedTags parses a non-standard multi-game format in which a
+// single tag section at the top of pgn is shared by every game, and the
+// games that follow it may consist of movetext only:
+//
+//	[Event "Club Championship"]
+//	[Site "London"]
+//
+//	1. e4 e5 2. Nf3 1-0
+//	1. d4 d5 0-1
+//
+// The leading tag section belongs to the first game and is copied into every
+// later one, except for the tags that describe a single game: Result,
+// PlyCount, FEN and SetUp. A later game may still have tags of its own; they are applied
+// on top of the shared tags, so a per-game tag overrides a shared tag of the
+// same name for that game only. The options are applied to the parser as for
+// NewGameReader. It returns an error, naming the game, at the first game
+// that fails to parse.
+func ParseWithSharedTags(pgn string, opts ...ParserOption) ([]*Game, error) {
+	gr := NewGameReader(strings.NewReader(strings.TrimPrefix(pgn, "\uFEFF")), opts...)
+	var games []*Game
+	for {
+		game, err := gr.Next()
+		if err == io.EOF {
+			return games, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("game %d: %w", len(games)+1, err)
+		}
+		if len(games) == 0 {
+			gr.p.sharedTags = make(map[string]string, len(game.Tags))
+			for key, value := range game.Tags {
+				if !perGameTags[key] {
+					gr.p.sharedTags[key] = value
+				}
+			}
+		}
+		games = append(games, game)
+	}
+}
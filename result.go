@@ -0,0 +1,200 @@
+package chessnote
+
+import "strings"
+
+// Outcome is the result of a game, as recorded in its movetext or tags.
+type Outcome int
+
+const (
+	// OutcomeUnknown means the result is missing or not a recognized value.
+	// It is the zero value for Outcome.
+	OutcomeUnknown Outcome = iota
+	// OutcomeWhiteWins is the result "1-0".
+	OutcomeWhiteWins
+	// OutcomeBlackWins is the result "0-1".
+	OutcomeBlackWins
+	// OutcomeDraw is the result "1/2-1/2".
+	OutcomeDraw
+	// OutcomeOngoing is the result "*": the game is in progress, abandoned,
+	// or its result is otherwise unknown to the source.
+	OutcomeOngoing
+)
+
+// String returns the PGN result token for the outcome, or "" for
+// OutcomeUnknown.
+func (o Outcome) String() string {
+	switch o {
+	case OutcomeWhiteWins:
+		return "1-0"
+	case OutcomeBlackWins:
+		return "0-1"
+	case OutcomeDraw:
+		return "1/2-1/2"
+	case OutcomeOngoing:
+		return "*"
+	default:
+		return ""
+	}
+}
+
+// ParseOutcome converts a PGN result string into an Outcome. Surrounding
+// whitespace is ignored; any value other than the four result tokens maps
+// to OutcomeUnknown.
+func ParseOutcome(s string) Outcome {
+	switch strings.TrimSpace(s) {
+	case "1-0":
+		return OutcomeWhiteWins
+	case "0-1":
+		return OutcomeBlackWins
+	case "1/2-1/2":
+		return OutcomeDraw
+	case "*":
+		return OutcomeOngoing
+	default:
+		return OutcomeUnknown
+	}
+}
+
+// resultVariants maps non-standard spellings of results found in the wild,
+// with whitespace removed and letters lowercased, to their outcome.
+var resultVariants = map[string]Outcome{
+	"1:0":     OutcomeWhiteWins,
+	"0:1":     OutcomeBlackWins,
+	"½-½":     OutcomeDraw,
+	"½:½":     OutcomeDraw,
+	"1/2":     OutcomeDraw,
+	"½":       OutcomeDraw,
+	"0.5-0.5": OutcomeDraw,
+	"draw":    OutcomeDraw,
+}
+
+// parseResultVariant is like ParseOutcome, but also accepts the spellings in
+// resultVariants and ignores whitespace anywhere in s, as in "1 - 0".
+func parseResultVariant(s string) Outcome {
+	s = strings.Join(strings.Fields(s), "")
+	if outcome := ParseOutcome(s); outcome != OutcomeUnknown {
+		return outcome
+	}
+	return resultVariants[strings.ToLower(s)]
+}
+
+// Outcome returns the result of the game. The result token that terminates
+// the movetext takes precedence; if the game has none, the Result tag is
+// used. Both are interpreted with ParseOutcome.
+func (g *Game) Outcome() Outcome {
+	if g.Result != "" {
+		return ParseOutcome(g.Result)
+	}
+	return ParseOutcome(g.Tags["Result"])
+}
+
+// ResultFromBoard replays the mainline and returns the result its final
+// position implies, regardless of the recorded result: the side to move
+// loses if it is checkmated, and the game is drawn by stalemate or if
+// neither side has mating material (see Board.IsInsufficientMaterial). It
+// reports false if the final position is not terminal or the mainline
+// cannot be replayed. Draws by agreement, repetition or the fifty-move rule
+// and decisions by resignation or time cannot be read from the board.
+func (g *Game) ResultFromBoard() (string, bool) {
+	final, ok := g.finalPosition()
+	if !ok {
+		return "", false
+	}
+	switch {
+	case final.IsCheckmate():
+		if final.SideToMove() == White {
+			return OutcomeBlackWins.String(), true
+		}
+		return OutcomeWhiteWins.String(), true
+	case final.IsStalemate(), final.IsInsufficientMaterial():
+		return OutcomeDraw.String(), true
+	}
+	return "", false
+}
+
+// ResultSource names where a game's result is recorded.
+type ResultSource int
+
+const (
+	// ResultSourceNone selects neither source. Passed to NormalizeResult,
+	// it leaves the game unchanged. It is the zero value for ResultSource.
+	ResultSourceNone ResultSource = iota
+	// ResultSourceMovetext is the result token that ends the movetext,
+	// stored in Game.Result.
+	ResultSourceMovetext
+	// ResultSourceTag is the Result tag, stored in Game.Tags["Result"].
+	ResultSourceTag
+)
+
+// ResultConsistent reports whether the movetext result token and the Result
+// tag agree, as interpreted by ParseOutcome. A game that records its result
+// in only one place, or in neither, is consistent.
+func (g *Game) ResultConsistent() bool {
+	tag, ok := g.Tags["Result"]
+	if !ok || g.Result == "" {
+		return true
+	}
+	return ParseOutcome(g.Result) == ParseOutcome(tag)
+}
+
+// NormalizeResult makes the movetext result token and the Result tag agree by
+// copying the result recorded by prefer over the other one. It does nothing
+// if prefer is ResultSourceNone or the preferred source holds no recognized
+// result, and it reports whether the game was changed. A result written in a
+// non-standard form, such as "1 - 0", is only recognized once
+// CanonicalizeResult has rewritten it.
+func (g *Game) NormalizeResult(prefer ResultSource) bool {
+	var outcome Outcome
+	switch prefer {
+	case ResultSourceMovetext:
+		outcome = ParseOutcome(g.Result)
+	case ResultSourceTag:
+		outcome = ParseOutcome(g.Tags["Result"])
+	}
+	if outcome == OutcomeUnknown {
+		return false
+	}
+
+	want := outcome.String()
+	changed := false
+	if g.Result != want {
+		g.Result, changed = want, true
+	}
+	if g.Tags["Result"] != want {
+		if g.Tags == nil {
+			g.Tags = make(map[string]string)
+		}
+		g.Tags["Result"], changed = want, true
+	}
+	return changed
+}
+
+// CanonicalizeResult rewrites the movetext result token and the Result tag
+// into one of the four standard tokens when they hold a common non-standard
+// spelling, such as "½-½", "1 - 0", "1:0" or "draw" (see
+// parseResultVariant). Each is rewritten on its own, so a game whose two
+// results disagree keeps disagreeing; use NormalizeResult, which takes the
+// source to prefer, to reconcile them, typically after CanonicalizeResult.
+// A missing or unrecognized result is left unchanged. It reports whether
+// the game was changed.
+func (g *Game) CanonicalizeResult() bool {
+	changed := false
+	if outcome := parseResultVariant(g.Result); outcome != OutcomeUnknown && g.Result != outcome.String() {
+		g.Result, changed = outcome.String(), true
+	}
+	if tag, ok := g.Tags["Result"]; ok {
+		if outcome := parseResultVariant(tag); outcome != OutcomeUnknown && tag != outcome.String() {
+			g.Tags["Result"], changed = outcome.String(), true
+		}
+	}
+	return changed
+}
+
+// EndComment returns the commentary written after the game's result token,
+// which is kept in ResultComments, joined by newlines. A comment written
+// between the last move and the result is not included: like any comment
+// following a move, it is attached to that final move's Comments. It returns
+// "" if there is no comment after the result.
+func (g *Game) EndComment() string {
+	return strings.Join(g.ResultComments, "\n")
+}
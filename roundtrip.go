@@ -0,0 +1,126 @@
+package chessnote
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// RoundTrip encodes the game with ToPGN and parses the result back with the
+// default parser options. Comparing the returned game with the original
+// using DiffGames shows whether the game survives being written as PGN,
+// which is a quick way to check that an edited or generated game is well
+// formed.
+func RoundTrip(g *Game) (*Game, error) {
+	parsed, err := ParseString(g.ToPGN())
+	if err != nil {
+		return nil, fmt.Errorf("re-parsing encoded game: %w", err)
+	}
+	return parsed, nil
+}
+
+// Equal reports whether g and other have the same tags, moves, variations,
+// annotations, comments and result, as described by DiffGames.
+func (g *Game) Equal(other *Game) bool {
+	return len(DiffGames(g, other)) == 0
+}
+
+// DiffGames describes the structural differences between two games, one
+// string per difference, such as `Moves[4]: "Nf3" != "Nc3"` or
+// `Moves[2].Variations[0]: 3 moves != 2 moves`. It returns nil if the games
+// are equal. Tags, the result, game and move comments, NAGs, variations and
+// the fields that describe each move as written in PGN are compared; nil and
+// empty slices are treated alike. A move's IsEnPassant and Timestamp are not
+// compared, since they are derived from the position and from its comments.
+// ParseWarnings and Trailing, which describe how a game was parsed rather
+// than the game itself, are ignored.
+func DiffGames(a, b *Game) []string {
+	var diffs []string
+	for _, key := range a.TagKeys() {
+		if bv, ok := b.Tags[key]; !ok {
+			diffs = append(diffs, fmt.Sprintf("tag %s: %q != missing", key, a.Tags[key]))
+		} else if av := a.Tags[key]; av != bv {
+			diffs = append(diffs, fmt.Sprintf("tag %s: %q != %q", key, av, bv))
+		}
+	}
+	for _, key := range b.TagKeys() {
+		if _, ok := a.Tags[key]; !ok {
+			diffs = append(diffs, fmt.Sprintf("tag %s: missing != %q", key, b.Tags[key]))
+		}
+	}
+	if a.Result != b.Result {
+		diffs = append(diffs, fmt.Sprintf("result: %q != %q", a.Result, b.Result))
+	}
+	diffs = diffStrings(diffs, "Comments", a.Comments, b.Comments)
+	diffs = diffStrings(diffs, "ResultComments", a.ResultComments, b.ResultComments)
+	return diffLine(diffs, "Moves", a.Moves, b.Moves)
+}
+
+// diffLine appends the differences between two lines of moves, located at
+// path, and recursively between their variations.
+func diffLine(diffs []string, path string, a, b []Move) []string {
+	if len(a) != len(b) {
+		diffs = append(diffs, fmt.Sprintf("%s: %d moves != %d moves", path, len(a), len(b)))
+	}
+	for i := 0; i < len(a) && i < len(b); i++ {
+		diffs = diffMove(diffs, fmt.Sprintf("%s[%d]", path, i), a[i], b[i])
+	}
+	return diffs
+}
+
+// diffMove appends the differences between two moves located at path.
+func diffMove(diffs []string, path string, a, b Move) []string {
+	if moveIdentity(a) != moveIdentity(b) {
+		if as, bs := a.String(), b.String(); as != bs {
+			diffs = append(diffs, fmt.Sprintf("%s: %q != %q", path, as, bs))
+		} else {
+			diffs = append(diffs, fmt.Sprintf("%s: %+v != %+v", path, moveIdentity(a), moveIdentity(b)))
+		}
+	}
+	if len(a.NAGs) != len(b.NAGs) || (len(a.NAGs) > 0 && !reflect.DeepEqual(a.NAGs, b.NAGs)) {
+		diffs = append(diffs, fmt.Sprintf("%s.NAGs: %v != %v", path, a.NAGs, b.NAGs))
+	}
+	diffs = diffStrings(diffs, path+".Comments", a.comments(), b.comments())
+	if len(a.Variations) != len(b.Variations) {
+		diffs = append(diffs, fmt.Sprintf("%s.Variations: %d != %d", path, len(a.Variations), len(b.Variations)))
+	}
+	for v := 0; v < len(a.Variations) && v < len(b.Variations); v++ {
+		diffs = diffLine(diffs, fmt.Sprintf("%s.Variations[%d]", path, v), a.Variations[v], b.Variations[v])
+	}
+	return diffs
+}
+
+// diffStrings appends a difference if two string slices differ.
+func diffStrings(diffs []string, path string, a, b []string) []string {
+	if len(a) != len(b) || (len(a) > 0 && !reflect.DeepEqual(a, b)) {
+		diffs = append(diffs, fmt.Sprintf("%s: %q != %q", path, a, b))
+	}
+	return diffs
+}
+
+// identity holds the fields that describe a move itself, as written in
+// PGN, leaving out its annotations, variations and IsEnPassant, which is
+// derived by replaying the game.
+type identity struct {
+	From, To                            Square
+	HasFromFile, HasFromRank            bool
+	Piece, Promotion                    PieceType
+	IsCapture, IsCheck, IsMate          bool
+	IsKingsideCastle, IsQueensideCastle bool
+}
+
+// moveIdentity returns the identity of m.
+func moveIdentity(m Move) identity {
+	return identity{
+		From:              m.From,
+		To:                m.To,
+		HasFromFile:       m.HasFromFile,
+		HasFromRank:       m.HasFromRank,
+		Piece:             m.Piece,
+		Promotion:         m.Promotion,
+		IsCapture:         m.IsCapture,
+		IsCheck:           m.IsCheck,
+		IsMate:            m.IsMate,
+		IsKingsideCastle:  m.IsKingsideCastle,
+		IsQueensideCastle: m.IsQueensideCastle,
+	}
+}
@@ -0,0 +1,155 @@
+package chessnote
+
+import "strings"
+
+// String returns the move in Standard Algebraic Notation, such as "Nbd2",
+// "exd8=Q+" or "O-O-O#". The notation is built from the move's fields alone:
+// the starting file and rank are written exactly when HasFromFile and
+// HasFromRank are set, and the check and mate suffixes follow IsCheck and
+// IsMate. Annotations, comments and variations are not included. Use
+// Board.SAN to derive the notation from a position instead.
+func (m Move) String() string {
+	var sb strings.Builder
+	switch {
+	case m.IsKingsideCastle:
+		sb.WriteString("O-O")
+	case m.IsQueensideCastle:
+		sb.WriteString("O-O-O")
+	default:
+		fromFile := m.HasFromFile || (m.Piece == Pawn && m.IsCapture)
+		writeMoveBody(&sb, m, fromFile, m.HasFromRank)
+	}
+	writeCheckSuffix(&sb, m.IsCheck, m.IsMate)
+	return sb.String()
+}
+
+// Coordinates returns the starting and destination squares of the move as
+// recorded by the parser, such as "g1" and "f3", with "?" standing for each
+// component that is not known. The starting square is usually partial:
+// "Nf3" yields "??" and "f3", "Nbd2" yields "b?" and "d2" and "N1c3" yields
+// "?1" and "c3". Castling moves record no squares, so both are "??". Use
+// Board.Apply or Board.SAN to resolve the full origin from a position.
+func (m Move) Coordinates() (from, to string) {
+	if m.IsKingsideCastle || m.IsQueensideCastle {
+		return "??", "??"
+	}
+	f := []byte("??")
+	if m.HasFromFile {
+		f[0] = byte('a' + m.From.File)
+	}
+	if m.HasFromRank {
+		f[1] = byte('1' + m.From.Rank)
+	}
+	return string(f), squareName(m.To)
+}
+
+// SAN returns the Standard Algebraic Notation of m played in the position b.
+// The origin of m is resolved like Apply does, and the notation then includes
+// the minimal disambiguation the position requires: none when only one such
+// piece can reach the destination, otherwise the starting file, the rank if
+// the file is shared, or both. The capture marker is taken from the board and
+// the check and mate suffixes from the resulting position, so the matching
+// fields of m are ignored. It returns an error if m is not legal in b.
+func (b *Board) SAN(m Move) (string, error) {
+	next := *b
+	if err := next.Apply(m); err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	switch {
+	case m.IsKingsideCastle:
+		sb.WriteString("O-O")
+	case m.IsQueensideCastle:
+		sb.WriteString("O-O-O")
+	default:
+		from, err := b.resolveOrigin(m)
+		if err != nil {
+			return "", err
+		}
+		m.From = from
+		m.IsCapture = !b.PieceAt(m.To).IsEmpty() || b.isEnPassant(from, m)
+		fromFile, fromRank := b.disambiguation(from, m)
+		writeMoveBody(&sb, m, fromFile, fromRank)
+	}
+
+	check := next.IsCheck()
+	writeCheckSuffix(&sb, check, next.IsCheckmate())
+	return sb.String(), nil
+}
+
+// MovesInSAN returns the Standard Algebraic Notation of each mainline move,
+// in order and without move numbers, such as ["e4", "e5", "Nf3"]. The game
+// is replayed so that each move is written as Board.SAN derives it from its
+// position, with the disambiguation, capture marker and check suffix the
+// position requires. From the first move that cannot be replayed, if any,
+// moves are written as Move.String writes them.
+func (g *Game) MovesInSAN() []string {
+	sans := make([]string, len(g.Moves))
+	b, err := g.InitialBoard()
+	for i, m := range g.Moves {
+		if err == nil {
+			if sans[i], err = b.SAN(m); err == nil {
+				err = b.Apply(m)
+				continue
+			}
+		}
+		sans[i] = m.String()
+	}
+	return sans
+}
+
+// disambiguation reports whether the SAN of m, played from the given origin,
+// must name the starting file and rank to tell it apart from moves by other
+// pieces of the same type to the same square.
+func (b *Board) disambiguation(from Square, m Move) (file, rank bool) {
+	if m.Piece == Pawn {
+		return m.IsCapture, false
+	}
+	bare := m
+	bare.From, bare.HasFromFile, bare.HasFromRank = Square{}, false, false
+	others := filterOrigins(b.legalOrigins(bare), func(sq Square) bool { return sq != from })
+	if len(others) == 0 {
+		return false, false
+	}
+	if len(filterOrigins(others, func(sq Square) bool { return sq.File == from.File })) == 0 {
+		return true, false
+	}
+	if len(filterOrigins(others, func(sq Square) bool { return sq.Rank == from.Rank })) == 0 {
+		return false, true
+	}
+	return true, true
+}
+
+// writeMoveBody writes the SAN of a non-castling move without its check
+// suffix, naming the starting file and rank as requested.
+func writeMoveBody(sb *strings.Builder, m Move, fromFile, fromRank bool) {
+	if m.Piece != Pawn {
+		sb.WriteByte("PNBRQK"[m.Piece])
+	}
+	if fromFile {
+		sb.WriteByte(byte('a' + m.From.File))
+	}
+	if fromRank {
+		sb.WriteByte(byte('1' + m.From.Rank))
+	}
+	if m.IsCapture {
+		sb.WriteByte('x')
+	}
+	sb.WriteString(squareName(m.To))
+	if m.Piece == Pawn && m.Promotion != Pawn {
+		sb.WriteByte('=')
+		sb.WriteByte("PNBRQK"[m.Promotion])
+	}
+}
+
+// writeCheckSuffix writes "#" for a mate, "+" for a check and nothing
+// otherwise.
+func writeCheckSuffix(sb *strings.Builder, check, mate bool) {
+	switch {
+	case mate:
+		sb.WriteByte('#')
+	case check:
+		sb.WriteByte('+')
+	}
+}
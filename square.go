@@ -0,0 +1,59 @@
+package chessnote
+
+import "fmt"
+
+// ToAlgebraic returns the square in algebraic notation, such as "e4". It
+// returns "" if the square is off the board.
+func (s Square) ToAlgebraic() string {
+	if !onBoard(s) {
+		return ""
+	}
+	return squareName(s)
+}
+
+// SquareFromAlgebraic parses a square in algebraic notation, such as "e4".
+func SquareFromAlgebraic(s string) (Square, error) {
+	sq, ok := newSquare(s)
+	if !ok {
+		return Square{}, fmt.Errorf("invalid square %q", s)
+	}
+	return sq, nil
+}
+
+// Index returns the square as an index from 0 to 63, counting along the
+// ranks from a1 = 0 through h1 = 7 and a8 = 56 to h8 = 63. It returns -1 if
+// the square is off the board.
+func (s Square) Index() int {
+	if !onBoard(s) {
+		return -1
+	}
+	return s.Rank*8 + s.File
+}
+
+// SquareFromIndex returns the square with the given 0-63 index, as described
+// by Square.Index.
+func SquareFromIndex(i int) (Square, error) {
+	if i < 0 || i > 63 {
+		return Square{}, fmt.Errorf("invalid square index %d", i)
+	}
+	return Square{File: i % 8, Rank: i / 8}, nil
+}
+
+// To0x88 returns the square's index on a 0x88 board, where the rank occupies
+// the high nibble and the file the low nibble, so a1 = 0x00, h1 = 0x07 and
+// a8 = 0x70. It returns -1 if the square is off the board.
+func (s Square) To0x88() int {
+	if !onBoard(s) {
+		return -1
+	}
+	return s.Rank<<4 | s.File
+}
+
+// SquareFrom0x88 returns the square with the given 0x88 index, as described
+// by Square.To0x88.
+func SquareFrom0x88(i int) (Square, error) {
+	if i < 0 || i > 0x77 || i&0x88 != 0 {
+		return Square{}, fmt.Errorf("invalid 0x88 square index %#x", i)
+	}
+	return Square{File: i & 7, Rank: i >> 4}, nil
+}
@@ -0,0 +1,77 @@
+package chessnote
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Summary returns a short, human-readable description of the game for
+// command-line output, one "Label: value" line per field:
+//
+//	White:     Carlsen, Magnus (2830)
+//	Black:     Nakamura, Hikaru (2789)
+//	Event:     Candidates
+//	Site:      Toronto
+//	Date:      2024.04.04
+//	Result:    1/2-1/2
+//	Plies:     2
+//	Annotated: no
+//
+// Missing tags are shown as "?", as in PGN, and ratings are only shown when
+// available. The result is the one reported by Outcome, or "?" if it is not
+// known. A game counts as annotated if any of its moves, including those in
+// variations, carries a comment, NAG or variation, or if it has comments
+// before its first move or after its result.
+func (g *Game) Summary() string {
+	var sb strings.Builder
+	line := func(label, value string) {
+		sb.WriteString(label)
+		sb.WriteString(":")
+		sb.WriteString(strings.Repeat(" ", 10-len(label)))
+		sb.WriteString(value)
+		sb.WriteByte('\n')
+	}
+
+	line("White", player(g.Tags["White"], g.WhiteElo))
+	line("Black", player(g.Tags["Black"], g.BlackElo))
+	line("Event", tagOrUnknown(g.Tags["Event"]))
+	line("Site", tagOrUnknown(g.Tags["Site"]))
+	line("Date", tagOrUnknown(g.Tags["Date"]))
+	line("Result", tagOrUnknown(g.Outcome().String()))
+	line("Plies", strconv.Itoa(len(g.Moves)))
+	if g.isAnnotated() {
+		line("Annotated", "yes")
+	} else {
+		line("Annotated", "no")
+	}
+	return sb.String()
+}
+
+// player formats a player's name followed by their rating, if known.
+func player(name string, elo func() (int, bool)) string {
+	name = tagOrUnknown(name)
+	if rating, ok := elo(); ok {
+		return name + " (" + strconv.Itoa(rating) + ")"
+	}
+	return name
+}
+
+// tagOrUnknown returns value, or "?" if it is empty.
+func tagOrUnknown(value string) string {
+	if value == "" {
+		return "?"
+	}
+	return value
+}
+
+// isAnnotated reports whether the game has any comments, NAGs or
+// variations.
+func (g *Game) isAnnotated() bool {
+	if len(g.Comments) > 0 || len(g.ResultComments) > 0 {
+		return true
+	}
+	annotated := g.FindMoves(func(m Move) bool {
+		return len(m.Comments) > 0 || len(m.commentSpans) > 0 || len(m.NAGs) > 0 || len(m.Variations) > 0
+	})
+	return len(annotated) > 0
+}
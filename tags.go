@@ -0,0 +1,157 @@
+package chessnote
+
+import (
+	"sort"
+	"strconv"
+)
+
+// sevenTagRoster lists the tags every PGN game must carry, in the order the
+// PGN standard requires them to be exported.
+var sevenTagRoster = []string{"Event", "Site", "Date", "Round", "White", "Black", "Result"}
+
+// extensionTags lists common supplemental tags, including the FIDE player
+// and event extensions, in the order they are exported after the Seven Tag
+// Roster.
+var extensionTags = []string{
+	"WhiteTitle", "BlackTitle", "WhiteElo", "BlackElo",
+	"WhiteFideId", "BlackFideId", "WhiteTeam", "BlackTeam",
+	"EventDate", "EventSponsor", "Section", "Stage", "Board",
+	"ECO", "Opening", "Variation", "SubVariation",
+	"TimeControl", "Time", "UTCDate", "UTCTime",
+	"Termination", "Annotator", "Mode", "PlyCount",
+	"SetUp", "FEN",
+}
+
+// tagRank maps each standard or extension tag to its export position.
+var tagRank = func() map[string]int {
+	ranks := make(map[string]int, len(sevenTagRoster)+len(extensionTags))
+	for i, key := range append(append([]string(nil), sevenTagRoster...), extensionTags...) {
+		ranks[key] = i
+	}
+	return ranks
+}()
+
+// TagKeys returns the names of the game's tags in the order a PGN writer
+// should emit them: the Seven Tag Roster first, in its standard order, then
+// the common extension tags such as WhiteTitle, WhiteElo, WhiteFideId and
+// EventDate, then any remaining tags sorted alphabetically.
+func (g *Game) TagKeys() []string {
+	keys := make([]string, 0, len(g.Tags))
+	for key := range g.Tags {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		ri, iKnown := tagRank[keys[i]]
+		rj, jKnown := tagRank[keys[j]]
+		switch {
+		case iKnown && jKnown:
+			return ri < rj
+		case iKnown != jKnown:
+			return iKnown
+		default:
+			return keys[i] < keys[j]
+		}
+	})
+	return keys
+}
+
+// WhiteTitle returns the FIDE title of the White player, such as "GM", from
+// the WhiteTitle tag, or "" if the game has none.
+func (g *Game) WhiteTitle() string {
+	return g.Tags["WhiteTitle"]
+}
+
+// BlackTitle returns the FIDE title of the Black player, such as "GM", from
+// the BlackTitle tag, or "" if the game has none.
+func (g *Game) BlackTitle() string {
+	return g.Tags["BlackTitle"]
+}
+
+// WhiteFideID returns the FIDE identifier of the White player from the
+// WhiteFideId tag, or "" if the game has none.
+func (g *Game) WhiteFideID() string {
+	return g.Tags["WhiteFideId"]
+}
+
+// BlackFideID returns the FIDE identifier of the Black player from the
+// BlackFideId tag, or "" if the game has none.
+func (g *Game) BlackFideID() string {
+	return g.Tags["BlackFideId"]
+}
+
+// EventDate returns the starting date of the event from the EventDate tag,
+// in the PGN "YYYY.MM.DD" format, or "" if the game has none.
+func (g *Game) EventDate() string {
+	return g.Tags["EventDate"]
+}
+
+// Annotator returns the name of the person who annotated the game, from the
+// Annotator tag, or "" if the game has none. When encoding, ToPGN writes the
+// tag with the other extension tags, after Termination.
+func (g *Game) Annotator() string {
+	return g.Tags["Annotator"]
+}
+
+// DeclaredPlyCount returns the number of half-moves stated by the game's
+// PlyCount tag. It returns false if the tag is missing or is not a
+// non-negative integer. Compare it with len(g.Moves) to detect a truncated
+// game; Validate does so.
+func (g *Game) DeclaredPlyCount() (int, bool) {
+	value, ok := g.Tags["PlyCount"]
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	return n, true
+}
+
+// WhiteElo returns the White player's rating from the WhiteElo tag. It
+// returns false if the tag is missing or does not hold a positive integer,
+// as with the "-" or "0" some exporters write for unrated players.
+func (g *Game) WhiteElo() (int, bool) {
+	return parseElo(g.Tags["WhiteElo"])
+}
+
+// BlackElo returns the Black player's rating from the BlackElo tag, as
+// described for WhiteElo.
+func (g *Game) BlackElo() (int, bool) {
+	return parseElo(g.Tags["BlackElo"])
+}
+
+// AverageElo returns the mean of the two players' ratings, rounded down. It
+// returns false unless both WhiteElo and BlackElo are available.
+func (g *Game) AverageElo() (int, bool) {
+	white, ok := g.WhiteElo()
+	if !ok {
+		return 0, false
+	}
+	black, ok := g.BlackElo()
+	if !ok {
+		return 0, false
+	}
+	return (white + black) / 2, true
+}
+
+// RatingCategory returns the 100-point band containing the game's average
+// rating, such as "2200-2299", for grouping a database by strength. It
+// returns "" if the average rating is not available.
+func (g *Game) RatingCategory() string {
+	avg, ok := g.AverageElo()
+	if !ok {
+		return ""
+	}
+	low := avg / 100 * 100
+	return strconv.Itoa(low) + "-" + strconv.Itoa(low+99)
+}
+
+// parseElo parses the value of a rating tag.
+func parseElo(value string) (int, bool) {
+	elo, err := strconv.Atoi(value)
+	if err != nil || elo <= 0 {
+		return 0, false
+	}
+	return elo, true
+}
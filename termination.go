@@ -0,0 +1,78 @@
+package chessnote
+
+import "strings"
+
+// Termination describes how a game ended, as recorded by the Termination tag
+// of the PGN standard. It is a string type so that values other than the
+// predefined constants, such as those of a particular site, are kept rather
+// than lost; the empty Termination means the reason is unknown.
+type Termination string
+
+// The standard values of the Termination tag, plus the more specific
+// Resignation and Agreement that are only recognized in comments.
+const (
+	TerminationNormal          Termination = "normal"
+	TerminationTimeForfeit     Termination = "time forfeit"
+	TerminationAbandoned       Termination = "abandoned"
+	TerminationAdjudication    Termination = "adjudication"
+	TerminationDeath           Termination = "death"
+	TerminationEmergency       Termination = "emergency"
+	TerminationRulesInfraction Termination = "rules infraction"
+	TerminationUnterminated    Termination = "unterminated"
+	TerminationResignation     Termination = "resignation"
+	TerminationAgreement       Termination = "agreement"
+)
+
+// terminationPhrases maps phrases found in result comments, such as
+// {White wins by resignation}, to the termination they describe. They are
+// tried in order against the lowercased comment.
+var terminationPhrases = []struct {
+	phrase      string
+	termination Termination
+}{
+	{"resign", TerminationResignation},
+	{"on time", TerminationTimeForfeit},
+	{"time forfeit", TerminationTimeForfeit},
+	{"flag", TerminationTimeForfeit},
+	{"abandon", TerminationAbandoned},
+	{"adjudicat", TerminationAdjudication},
+	{"agreed", TerminationAgreement},
+	{"agreement", TerminationAgreement},
+	{"checkmate", TerminationNormal},
+	{"stalemate", TerminationNormal},
+}
+
+// ParseTermination converts a Termination tag value into a Termination.
+// Matching is case-insensitive and ignores surrounding whitespace, so
+// Lichess values such as "Time forfeit" map to TerminationTimeForfeit. Other
+// values are returned lowercased.
+func ParseTermination(s string) Termination {
+	return Termination(strings.ToLower(strings.TrimSpace(s)))
+}
+
+// Termination returns how the game ended. The Termination tag takes
+// precedence. Without one, the comment that closes the game is scanned for
+// common phrases such as "resigns", "on time" or "draw agreed"; that is the
+// first comment after the result, or else the last comment of the final
+// mainline move. It returns "" if the reason cannot be determined.
+func (g *Game) Termination() Termination {
+	if tag, ok := g.Tags["Termination"]; ok && strings.TrimSpace(tag) != "" {
+		return ParseTermination(tag)
+	}
+	var comment string
+	switch {
+	case len(g.ResultComments) > 0:
+		comment = g.ResultComments[0]
+	case len(g.Moves) > 0:
+		if comments := g.Moves[len(g.Moves)-1].comments(); len(comments) > 0 {
+			comment = comments[len(comments)-1]
+		}
+	}
+	comment = strings.ToLower(comment)
+	for _, p := range terminationPhrases {
+		if strings.Contains(comment, p.phrase) {
+			return p.termination
+		}
+	}
+	return ""
+}
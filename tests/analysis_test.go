@@ -0,0 +1,292 @@
+package chessnote_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/YashBhalodi/chessnote"
+)
+
+func TestParseCommands(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		comment string
+		want    []chessnote.Command
+	}{
+		{"no commands", "A quiet move", nil},
+		{
+			name:    "several commands",
+			comment: "[%eval 0.3] [%depth 24] [%pv e2e4 e7e5]",
+			want: []chessnote.Command{
+				{Name: "eval", Value: "0.3"},
+				{Name: "depth", Value: "24"},
+				{Name: "pv", Value: "e2e4 e7e5"},
+			},
+		},
+		{
+			name:    "commands mixed with text",
+			comment: "Strong. [%clk 0:03:12] Black is lost.",
+			want:    []chessnote.Command{{Name: "clk", Value: "0:03:12"}},
+		},
+		{"command without value", "[%novelty]", []chessnote.Command{{Name: "novelty"}}},
+		{"unterminated command", "[%eval 0.3", nil},
+		{"spaced inside the brackets", "[ %clk  0:00:30 ]", []chessnote.Command{{Name: "clk", Value: "0:00:30"}}},
+		{"tab after the name", "[%clk\t0:00:30]", []chessnote.Command{{Name: "clk", Value: "0:00:30"}}},
+		{
+			name:    "plain brackets before a command",
+			comment: "[sic] [%clk 0:00:30]",
+			want:    []chessnote.Command{{Name: "clk", Value: "0:00:30"}},
+		},
+		{
+			name:    "three commands back to back",
+			comment: "[%evp 0,15,22,-8][%clk 0:01:00][%eval 0.3]",
+			want: []chessnote.Command{
+				{Name: "evp", Value: "0,15,22,-8"},
+				{Name: "clk", Value: "0:01:00"},
+				{Name: "eval", Value: "0.3"},
+			},
+		},
+		{
+			name:    "three commands in one pair of brackets",
+			comment: "[%evp 0,15 %clk 0:01:00\n%emt 0:00:05]",
+			want: []chessnote.Command{
+				{Name: "evp", Value: "0,15"},
+				{Name: "clk", Value: "0:01:00"},
+				{Name: "emt", Value: "0:00:05"},
+			},
+		},
+		{"nested brackets", "[%evp 0,[15,22],[-8]] Good", []chessnote.Command{{Name: "evp", Value: "0,[15,22],[-8]"}}},
+		{"equals after the name", "[%evp=0,15,22]", []chessnote.Command{{Name: "evp", Value: "0,15,22"}}},
+		{"spaced equals after the name", "[%evp = 0,15]", []chessnote.Command{{Name: "evp", Value: "0,15"}}},
+		{"percent inside a value", "[%note 50%]", []chessnote.Command{{Name: "note", Value: "50%"}}},
+		{"unterminated nested brackets", "[%evp [0,15]", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := chessnote.ParseCommands(tt.comment); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseCommands(%q) = %+v, want %+v", tt.comment, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLichessClockComments(t *testing.T) {
+	t.Parallel()
+	game, err := chessnote.ParseString("1. e4 { [%clk 0:00:30] } 1... e5 {[%clk 0:00:29]} 2. Nf3 {  [%clk   0:00:28]  } *")
+	if err != nil {
+		t.Fatalf("ParseString() failed: %v", err)
+	}
+	want := []string{"0:00:30", "0:00:29", "0:00:28"}
+	for i, m := range game.Moves {
+		if len(m.Comments) != 1 {
+			t.Fatalf("move %d: expected 1 comment, got %d", i, len(m.Comments))
+		}
+		got := chessnote.ParseCommands(m.Comments[0])
+		if len(got) != 1 || got[0] != (chessnote.Command{Name: "clk", Value: want[i]}) {
+			t.Errorf("move %d: ParseCommands(%q) = %+v, want the clock %s", i, m.Comments[0], got, want[i])
+		}
+	}
+}
+
+func TestParseAnalysis(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		comment string
+		want    chessnote.Analysis
+	}{
+		{"depth and UCI pv", "[%eval 0.3] [%depth 24] [%pv e2e4 e7e5]", chessnote.Analysis{HasEval: true, Eval: 0.3, Depth: 24, PV: []string{"e2e4", "e7e5"}}},
+		{"negative eval", "[%eval -1.25]", chessnote.Analysis{HasEval: true, Eval: -1.25}},
+		{"eval with depth suffix", "[%eval 0.17,22]", chessnote.Analysis{HasEval: true, Eval: 0.17, Depth: 22}},
+		{"white mates", "[%eval #3]", chessnote.Analysis{HasEval: true, Mate: 3}},
+		{"black mates", "[%eval #-2]", chessnote.Analysis{HasEval: true, Mate: -2}},
+		{"malformed eval", "[%eval big]", chessnote.Analysis{}},
+		{"SAN pv with move numbers", "[%pv 2. Nf3 Nc6 3. Bb5]", chessnote.Analysis{PV: []string{"Nf3", "Nc6", "Bb5"}}},
+		{"malformed depth", "[%depth deep]", chessnote.Analysis{}},
+		{"no analysis", "[%clk 0:01:00]", chessnote.Analysis{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := chessnote.ParseAnalysis(tt.comment); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseAnalysis(%q) = %+v, want %+v", tt.comment, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoveAnalysisPVMoves(t *testing.T) {
+	t.Parallel()
+	pgn := `1. e4 {[%eval 0.3] [%depth 24] [%pv e7e5 g1f3 b8c6]} e5 {[%depth 20]} {[%pv Nf3 Nc6 Bb5]} 2. Nf3 {[%pv e8e6]} *`
+	game, err := chessnote.ParseString(pgn)
+	if err != nil {
+		t.Fatalf("ParseString() failed: %v", err)
+	}
+	positions, err := game.Positions()
+	if err != nil {
+		t.Fatalf("Positions() failed: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		ply       int
+		wantDepth int
+		wantSAN   []string
+		wantErr   bool
+	}{
+		{"UCI pv", 1, 24, []string{"e5", "Nf3", "Nc6"}, false},
+		{"SAN pv across comments", 2, 20, []string{"Nf3", "Nc6", "Bb5"}, false},
+		{"illegal pv", 3, 0, []string{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := game.Moves[tt.ply-1].Analysis()
+			if a.Depth != tt.wantDepth {
+				t.Errorf("got depth %d, want %d", a.Depth, tt.wantDepth)
+			}
+			start := positions[tt.ply]
+			moves, err := a.PVMoves(start)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PVMoves() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			b := *start
+			got := []string{}
+			for _, m := range moves {
+				san, err := b.SAN(m)
+				if err != nil {
+					t.Fatalf("SAN() failed: %v", err)
+				}
+				got = append(got, san)
+				if err := b.Apply(m); err != nil {
+					t.Fatalf("Apply() failed: %v", err)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.wantSAN) {
+				t.Errorf("got PV %v, want %v", got, tt.wantSAN)
+			}
+		})
+	}
+}
+
+func TestBoardParseUCI(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		fen     string
+		uci     string
+		want    chessnote.Move
+		wantErr bool
+	}{
+		{
+			name: "pawn push",
+			fen:  chessnote.StartingFEN,
+			uci:  "e2e4",
+			want: chessnote.Move{Piece: chessnote.Pawn, From: chessnote.Square{File: 4, Rank: 1}, HasFromFile: true, HasFromRank: true, To: chessnote.Square{File: 4, Rank: 3}},
+		},
+		{
+			name: "promotion with capture",
+			fen:  "3r3k/4P3/8/8/8/8/8/4K3 w - - 0 1",
+			uci:  "e7d8q",
+			want: chessnote.Move{Piece: chessnote.Pawn, From: chessnote.Square{File: 4, Rank: 6}, HasFromFile: true, HasFromRank: true, To: chessnote.Square{File: 3, Rank: 7}, Promotion: chessnote.Queen, IsCapture: true},
+		},
+		{
+			name: "castling",
+			fen:  "4k3/8/8/8/8/8/8/R3K3 w Q - 0 1",
+			uci:  "e1c1",
+			want: chessnote.Move{Piece: chessnote.King, IsQueensideCastle: true},
+		},
+		{
+			name: "chess960 castling onto the rook",
+			fen:  "bqnbrkrn/pppppppp/8/8/8/8/PPPPPPPP/BQNBRKR1 w K - 0 1",
+			uci:  "f1g1",
+			want: chessnote.Move{Piece: chessnote.King, IsKingsideCastle: true},
+		},
+		{
+			name: "chess960 castling by king move",
+			fen:  "bqnbrkrn/pppppppp/8/8/8/8/PPPPPPPP/BQN1RK1R w KQ - 0 1",
+			uci:  "f1c1",
+			want: chessnote.Move{Piece: chessnote.King, IsQueensideCastle: true},
+		},
+		{
+			name: "king step is not castling",
+			fen:  "4k3/8/8/8/8/8/8/R3K3 w Q - 0 1",
+			uci:  "e1d1",
+			want: chessnote.Move{Piece: chessnote.King, From: chessnote.Square{File: 4}, HasFromFile: true, HasFromRank: true, To: chessnote.Square{File: 3}},
+		},
+		{name: "empty origin", fen: chessnote.StartingFEN, uci: "e4e5", wantErr: true},
+		{name: "wrong side", fen: chessnote.StartingFEN, uci: "e7e5", wantErr: true},
+		{name: "bad promotion", fen: "7k/4P3/8/8/8/8/8/4K3 w - - 0 1", uci: "e7e8k", wantErr: true},
+		{name: "malformed", fen: chessnote.StartingFEN, uci: "e2-e4", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := chessnote.ParseFEN(tt.fen)
+			if err != nil {
+				t.Fatalf("ParseFEN() failed: %v", err)
+			}
+			got, err := b.ParseUCI(tt.uci)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseUCI(%q) expected an error, but got nil", tt.uci)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseUCI(%q) error = %v", tt.uci, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseUCI(%q) = %+v, want %+v", tt.uci, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalysisScore(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		a    chessnote.Analysis
+		want float64
+	}{
+		{chessnote.Analysis{HasEval: true, Eval: -0.5}, -0.5},
+		{chessnote.Analysis{HasEval: true, Mate: 1}, 999},
+		{chessnote.Analysis{HasEval: true, Mate: 5}, 995},
+		{chessnote.Analysis{HasEval: true, Mate: -2}, -998},
+		{chessnote.Analysis{}, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.a.Score(); got != tt.want {
+			t.Errorf("%+v.Score() = %v, want %v", tt.a, got, tt.want)
+		}
+	}
+}
+
+func TestGameBlunders(t *testing.T) {
+	t.Parallel()
+	pgn := `1. e4 {[%eval 0.3]} e5 {[%eval 0.3]} 2. Nf3 {[%eval 0.2]} f6 {[%eval 1.6]}
+(2... Nc6 {[%eval 0.25]}) (2... Qh4 {[%eval 3.0]})
+3. Nxe5 {[%eval 1.5]} fxe5 {[%eval 1.2]} 4. Qh5+ {[%eval 1.3]} Ke7 {[%eval #3]}
+5. Qxe5+ {[%eval #2]} Kf7 6. Bc4+ {[%eval #1]} d5 {[%eval #1]} 7. Bxd5+ {[%eval -2.0]} *`
+	game, err := chessnote.ParseString(pgn)
+	if err != nil {
+		t.Fatalf("ParseString() failed: %v", err)
+	}
+
+	var got []int
+	var gotPaths [][]int
+	for _, ref := range game.Blunders(1.0) {
+		got = append(got, ref.Ply)
+		gotPaths = append(gotPaths, ref.Path)
+	}
+	// 2... f6 and 2... Qh4 lose material, 4... Ke7 allows mate, and 7. Bxd5+
+	// throws away a forced mate. 6. Bc4+ is skipped because 5... Kf7 has no
+	// evaluation, and 3... fxe5 improves Black's position.
+	wantPlies := []int{4, 4, 8, 13}
+	wantPaths := [][]int{{3}, {3, 1, 0}, {7}, {12}}
+	if !reflect.DeepEqual(got, wantPlies) || !reflect.DeepEqual(gotPaths, wantPaths) {
+		t.Errorf("Blunders() plies %v paths %v, want plies %v paths %v", got, gotPaths, wantPlies, wantPaths)
+	}
+
+	if refs := game.Blunders(2000); refs != nil {
+		t.Errorf("Blunders(2000) = %d moves, want none", len(refs))
+	}
+}
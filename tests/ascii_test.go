@@ -0,0 +1,50 @@
+package chessnote_test
+
+import (
+	"testing"
+
+	"github.com/YashBhalodi/chessnote"
+)
+
+func TestBoardToASCII(t *testing.T) {
+	t.Parallel()
+	want := `8 r n b q k b n r
+7 p p p p p p p p
+6 . . . . . . . .
+5 . . . . . . . .
+4 . . . . . . . .
+3 . . . . . . . .
+2 P P P P P P P P
+1 R N B Q K B N R
+  a b c d e f g h
+White to move
+`
+	if got := chessnote.NewBoard().ToASCII(); got != want {
+		t.Errorf("ToASCII() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestBoardToASCIIFrom(t *testing.T) {
+	t.Parallel()
+	b, err := chessnote.ParseFEN("rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1")
+	if err != nil {
+		t.Fatalf("ParseFEN() failed: %v", err)
+	}
+	want := `1 R N B K Q B N R
+2 P P P . P P P P
+3 . . . . . . . .
+4 . . . P . . . .
+5 . . . . . . . .
+6 . . . . . . . .
+7 p p p p p p p p
+8 r n b k q b n r
+  h g f e d c b a
+Black to move
+`
+	if got := b.ToASCIIFrom(chessnote.Black); got != want {
+		t.Errorf("ToASCIIFrom(Black) =\n%s\nwant:\n%s", got, want)
+	}
+	if got, want := b.ToASCIIFrom(chessnote.White), b.ToASCII(); got != want {
+		t.Errorf("ToASCIIFrom(White) =\n%s\nwant the ToASCII rendering:\n%s", got, want)
+	}
+}
@@ -0,0 +1,744 @@
+package chessnote_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/YashBhalodi/chessnote"
+)
+
+const operaGame = `
+[Event "A Night at the Opera"]
+[Result "1-0"]
+
+1. e4 e5 2. Nf3 d6 3. d4 Bg4 4. dxe5 Bxf3 5. Qxf3 dxe5 6. Bc4 Nf6 7. Qb3 Qe7
+8. Nc3 c6 9. Bg5 b5 10. Nxb5 cxb5 11. Bxb5+ Nbd7 12. O-O-O Rd8
+13. Rxd7 Rxd7 14. Rd1 Qe6 15. Bxd7+ Nxd7 16. Qb8+ Nxb8 17. Rd8# 1-0
+`
+
+func TestBoardGridStartingPosition(t *testing.T) {
+	t.Parallel()
+	grid := chessnote.NewBoard().Grid()
+
+	tests := []struct {
+		name       string
+		rank, file int
+		want       chessnote.Piece
+	}{
+		{"a1 white rook", 0, 0, chessnote.Piece{Type: chessnote.Rook, Color: chessnote.White}},
+		{"e1 white king", 0, 4, chessnote.Piece{Type: chessnote.King, Color: chessnote.White}},
+		{"d2 white pawn", 1, 3, chessnote.Piece{Type: chessnote.Pawn, Color: chessnote.White}},
+		{"e4 empty", 3, 4, chessnote.Piece{}},
+		{"g8 black knight", 7, 6, chessnote.Piece{Type: chessnote.Knight, Color: chessnote.Black}},
+		{"d8 black queen", 7, 3, chessnote.Piece{Type: chessnote.Queen, Color: chessnote.Black}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := grid[tt.rank][tt.file]; got != tt.want {
+				t.Errorf("Grid()[%d][%d] = %+v, want %+v", tt.rank, tt.file, got, tt.want)
+			}
+		})
+	}
+
+	if !grid[3][4].IsEmpty() {
+		t.Errorf("expected e4 to be empty")
+	}
+}
+
+func TestGamePositions(t *testing.T) {
+	t.Parallel()
+	game, err := chessnote.ParseString(operaGame)
+	if err != nil {
+		t.Fatalf("ParseString() failed: %v", err)
+	}
+
+	positions, err := game.Positions()
+	if err != nil {
+		t.Fatalf("Positions() failed: %v", err)
+	}
+	if len(positions) != len(game.Moves)+1 {
+		t.Fatalf("got %d positions, want %d", len(positions), len(game.Moves)+1)
+	}
+
+	// After 1. e4 the pawn has left e2 and Black is to move.
+	afterE4 := positions[1]
+	if !afterE4.PieceAt(chessnote.Square{File: 4, Rank: 1}).IsEmpty() {
+		t.Errorf("expected e2 to be empty after 1. e4")
+	}
+	if got := afterE4.PieceAt(chessnote.Square{File: 4, Rank: 3}); got != (chessnote.Piece{Type: chessnote.Pawn, Color: chessnote.White}) {
+		t.Errorf("got %+v on e4, want a white pawn", got)
+	}
+	if afterE4.SideToMove() != chessnote.Black {
+		t.Errorf("expected Black to move after 1. e4")
+	}
+
+	// After 12. O-O-O the king is on c1 and the rook on d1.
+	afterCastle := positions[23].Grid()
+	if afterCastle[0][2] != (chessnote.Piece{Type: chessnote.King, Color: chessnote.White}) {
+		t.Errorf("got %+v on c1, want the white king", afterCastle[0][2])
+	}
+	if afterCastle[0][3] != (chessnote.Piece{Type: chessnote.Rook, Color: chessnote.White}) {
+		t.Errorf("got %+v on d1, want a white rook", afterCastle[0][3])
+	}
+
+	final := positions[len(positions)-1].Grid()
+	if final[7][3] != (chessnote.Piece{Type: chessnote.Rook, Color: chessnote.White}) {
+		t.Errorf("got %+v on d8, want a white rook", final[7][3])
+	}
+	if final[7][4] != (chessnote.Piece{Type: chessnote.King, Color: chessnote.Black}) {
+		t.Errorf("got %+v on e8, want the black king", final[7][4])
+	}
+}
+
+func TestGamePositionsBlackToMoveFirst(t *testing.T) {
+	t.Parallel()
+	game, err := chessnote.ParseString(`[SetUp "1"]
+[FEN "rnbqkbnr/pppppppp/8/8/3P4/8/PPP1PPPP/RNBQKBNR b KQkq - 0 1"]
+1... d5 (1... Ngf6 2. c4 e5) 2. c4 (2. Nf3 Nf6) 2... e6 *`)
+	if err != nil {
+		t.Fatalf("ParseString() failed: %v", err)
+	}
+
+	positions, err := game.Positions()
+	if err != nil {
+		t.Fatalf("Positions() failed: %v", err)
+	}
+	if got := positions[1].PieceAt(chessnote.Square{File: 3, Rank: 4}); got != (chessnote.Piece{Type: chessnote.Pawn, Color: chessnote.Black}) {
+		t.Errorf("got %+v on d5, want a black pawn", got)
+	}
+	for i, b := range positions {
+		want := chessnote.Black
+		if i%2 == 1 {
+			want = chessnote.White
+		}
+		if b.SideToMove() != want {
+			t.Errorf("position %d: got %v to move, want %v", i, b.SideToMove(), want)
+		}
+	}
+	if errs := game.Validate(); errs != nil {
+		t.Errorf("Validate() = %v, want no problems", errs)
+	}
+
+	// The variations start from the side to move at their branch point.
+	refs := game.RedundantDisambiguations()
+	if len(refs) != 1 || !reflect.DeepEqual(refs[0].Path, []int{0, 0, 0}) {
+		t.Errorf("RedundantDisambiguations() = %+v, want only 1... Ngf6", refs)
+	}
+	for i, v := range game.VariationsAsGames() {
+		if _, err := v.Positions(); err != nil {
+			t.Errorf("variation %d: Positions() failed: %v", i, err)
+		}
+	}
+}
+
+func TestBoardApplyErrors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		pgn  string
+	}{
+		{"no piece can reach the square", "1. Nd4 *"},
+		{"pinned piece cannot move", "1. e4 e5 2. Nf3 d6 3. Bb5+ Nd7 4. Nc3 Nb6 *"},
+		{"ambiguous move", "1. Nf3 a6 2. Nc3 a5 3. Nb5 a4 4. Nd4 *"},
+		{"ambiguous move with a knight on the first rank", "1. d4 a6 2. Nf3 a5 3. Nd2 *"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game, err := chessnote.ParseString(tt.pgn)
+			if err != nil {
+				t.Fatalf("ParseString() failed: %v", err)
+			}
+			if _, err := game.Positions(); err == nil {
+				t.Errorf("Positions() expected an error, but got nil")
+			}
+		})
+	}
+}
+
+func TestGamePositionsReplayError(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		pgn  string
+		want chessnote.ReplayError
+	}{
+		{
+			name: "no piece can reach the square",
+			pgn:  "1. e4 e5 2. Nf4 *",
+			want: chessnote.ReplayError{
+				Ply:    3,
+				SAN:    "Nf4",
+				FEN:    "rnbqkbnr/pppp1ppp/8/4p3/4P3/8/PPPP1PPP/RNBQKBNR w KQkq e6 0 2",
+				Reason: "no knight can move to f4",
+			},
+		},
+		{
+			name: "king left in check",
+			pgn:  "1. e4 e5 2. Nf3 d6 3. Bb5+ Nd7 4. Nc3 Nb6 *",
+			want: chessnote.ReplayError{
+				Ply:    8,
+				SAN:    "Nb6",
+				FEN:    "r1bqkbnr/pppn1ppp/3p4/1B2p3/4P3/2N2N2/PPPP1PPP/R1BQK2R b KQkq - 3 4",
+				Reason: "knight to b6 would leave the king in check",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game, err := chessnote.ParseString(tt.pgn)
+			if err != nil {
+				t.Fatalf("ParseString() failed: %v", err)
+			}
+			_, err = game.Positions()
+			var replayErr *chessnote.ReplayError
+			if !errors.As(err, &replayErr) {
+				t.Fatalf("Positions() error = %v, want a *ReplayError", err)
+			}
+			if *replayErr != tt.want {
+				t.Errorf("got %+v, want %+v", *replayErr, tt.want)
+			}
+			if want := fmt.Sprintf("ply %d (%s): %s", tt.want.Ply, tt.want.SAN, tt.want.Reason); err.Error() != want {
+				t.Errorf("Error() = %q, want %q", err.Error(), want)
+			}
+		})
+	}
+}
+
+func TestBoardApplyCastling(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		fen     string
+		move    string
+		wantErr string
+	}{
+		{"kingside", "r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1", "O-O", ""},
+		{"queenside", "r3k2r/8/8/8/8/8/8/R3K2R b KQkq - 0 1", "O-O-O", ""},
+		{"queenside with b1 attacked", "1r2k3/8/8/8/8/8/8/R3K2R w KQ - 0 1", "O-O-O", ""},
+		{"forfeited right", "r3k2r/8/8/8/8/8/8/R3K2R w Qkq - 0 1", "O-O", "forfeited"},
+		{"piece in the way", "r3k2r/8/8/8/8/8/8/RN2K2R w KQkq - 0 1", "O-O-O", "b1 is occupied"},
+		{"out of check", "4k3/8/8/8/8/8/4r3/R3K2R w KQ - 0 1", "O-O", "out of check"},
+		{"through check", "4kr2/8/8/8/8/8/8/R3K2R w KQ - 0 1", "O-O", "f1 is attacked"},
+		{"into check", "4k1r1/8/8/8/8/8/8/R3K2R w KQ - 0 1", "O-O", "g1 is attacked"},
+		{"queenside through check", "3rk3/8/8/8/8/8/8/R3K2R w KQ - 0 1", "O-O-O", "d1 is attacked"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := chessnote.ParseFEN(tt.fen)
+			if err != nil {
+				t.Fatalf("ParseFEN() failed: %v", err)
+			}
+			m := chessnote.Move{Piece: chessnote.King, IsKingsideCastle: tt.move == "O-O", IsQueensideCastle: tt.move == "O-O-O"}
+			err = b.Apply(m)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Apply(%s) failed: %v", tt.move, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Apply(%s) error = %v, want it to mention %q", tt.move, err, tt.wantErr)
+			}
+			if b.FEN() != tt.fen {
+				t.Errorf("board changed after a failed castle: got %s", b.FEN())
+			}
+		})
+	}
+}
+
+func TestBoardApplyChess960Castling(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		fen     string
+		move    string
+		want    string // The position after the move, or "" if it is illegal.
+		wantErr string
+	}{
+		{
+			name: "king and rook swap squares",
+			fen:  "bqnbrkrn/pppppppp/8/8/8/8/PPPPPPPP/BQNBRKRN w KQkq - 0 1",
+			move: "O-O",
+			want: "bqnbrkrn/pppppppp/8/8/8/8/PPPPPPPP/BQNBRRKN b kq - 1 1",
+		},
+		{
+			name: "king already on its destination",
+			fen:  "4k3/8/8/8/8/8/8/R5KR w KQ - 0 1",
+			move: "O-O",
+			want: "4k3/8/8/8/8/8/8/R4RK1 b - - 1 1",
+		},
+		{
+			name: "queenside king already on its destination",
+			fen:  "4k3/8/8/8/8/8/8/R1K4R w KQ - 0 1",
+			move: "O-O-O",
+			want: "4k3/8/8/8/8/8/8/2KR3R b - - 1 1",
+		},
+		{
+			name: "rook already on its destination",
+			fen:  "4k3/8/8/8/8/8/8/4KR2 w K - 0 1",
+			move: "O-O",
+			want: "4k3/8/8/8/8/8/8/5RK1 b - - 1 1",
+		},
+		{
+			name: "king moves onto the rook's square",
+			fen:  "4k3/8/8/8/8/8/8/1R1K4 w Q - 0 1",
+			move: "O-O-O",
+			want: "4k3/8/8/8/8/8/8/2KR4 b - - 1 1",
+		},
+		{
+			name: "black castles",
+			fen:  "bqnbrkrn/pppppppp/8/8/8/8/PPPPPPPP/BQNBRRKN b kq - 1 1",
+			move: "O-O",
+			want: "bqnbrrkn/pppppppp/8/8/8/8/PPPPPPPP/BQNBRRKN w - - 2 2",
+		},
+		{
+			name:    "outer rook blocked by another rook",
+			fen:     "4k3/8/8/8/8/8/8/5RKR w K - 0 1",
+			move:    "O-O",
+			wantErr: "f1 is occupied",
+		},
+		{
+			name:    "rook shields the destination",
+			fen:     "4k3/8/8/8/8/8/8/qR4K1 w Q - 0 1",
+			move:    "O-O-O",
+			wantErr: "c1 is attacked",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := chessnote.ParseFEN(tt.fen)
+			if err != nil {
+				t.Fatalf("ParseFEN() failed: %v", err)
+			}
+			m := chessnote.Move{Piece: chessnote.King, IsKingsideCastle: tt.move == "O-O", IsQueensideCastle: tt.move == "O-O-O"}
+			err = b.Apply(m)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Apply(%s) error = %v, want it to mention %q", tt.move, err, tt.wantErr)
+				}
+				if b.FEN() != tt.fen {
+					t.Errorf("board changed after a failed castle: got %s", b.FEN())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Apply(%s) failed: %v", tt.move, err)
+			}
+			if got := b.FEN(); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("rights follow the castling rook", func(t *testing.T) {
+		for _, tt := range []struct{ fen, san, want string }{
+			{"4k3/8/8/8/8/8/8/1R2K1R1 w KQ - 0 1", "Rg2", "4k3/8/8/8/8/8/6R1/1R2K3 b Q - 1 1"},
+			{"4k3/8/8/8/8/8/8/RR2K3 w Q - 0 1", "Rb2", "4k3/8/8/8/8/8/1R6/R3K3 b Q - 1 1"},
+			{"1r2k3/8/8/8/8/8/8/1R2K3 w Q - 0 1", "Rxb8+", "1R2k3/8/8/8/8/8/8/4K3 b - - 0 1"},
+		} {
+			b, err := chessnote.ParseFEN(tt.fen)
+			if err != nil {
+				t.Fatalf("ParseFEN() failed: %v", err)
+			}
+			moves, err := chessnote.ParseMovetext(tt.san)
+			if err != nil {
+				t.Fatalf("ParseMovetext() failed: %v", err)
+			}
+			if err := b.Apply(moves[0]); err != nil {
+				t.Fatalf("Apply(%s) failed: %v", tt.san, err)
+			}
+			if got := b.FEN(); got != tt.want {
+				t.Errorf("after %s from %s: got %s, want %s", tt.san, tt.fen, got, tt.want)
+			}
+		}
+	})
+}
+
+func TestBoardApplyEnPassant(t *testing.T) {
+	t.Parallel()
+	game, err := chessnote.ParseString("1. e4 a6 2. e5 d5 3. exd6 *")
+	if err != nil {
+		t.Fatalf("ParseString() failed: %v", err)
+	}
+	positions, err := game.Positions()
+	if err != nil {
+		t.Fatalf("Positions() failed: %v", err)
+	}
+	after := positions[len(positions)-1]
+	d5, d6 := chessnote.Square{File: 3, Rank: 4}, chessnote.Square{File: 3, Rank: 5}
+	if !after.PieceAt(d5).IsEmpty() {
+		t.Errorf("got %+v on d5, want the captured pawn removed", after.PieceAt(d5))
+	}
+	if got := after.PieceAt(d6); got != (chessnote.Piece{Type: chessnote.Pawn, Color: chessnote.White}) {
+		t.Errorf("got %+v on d6, want the capturing white pawn", got)
+	}
+	if want := "rnbqkbnr/1pp1pppp/p2P4/8/8/8/PPPP1PPP/RNBQKBNR b KQkq - 0 3"; after.FEN() != want {
+		t.Errorf("FEN() = %s, want %s", after.FEN(), want)
+	}
+	for i, m := range game.Moves {
+		if m.IsEnPassant {
+			t.Errorf("move %d: Positions() set IsEnPassant on the game's move", i+1)
+		}
+		resolved, err := m.ResolveFrom(positions[i])
+		if err != nil {
+			t.Fatalf("move %d: ResolveFrom() failed: %v", i+1, err)
+		}
+		if resolved.IsEnPassant != (i == 4) {
+			t.Errorf("move %d: resolved IsEnPassant = %v, want %v", i+1, resolved.IsEnPassant, i == 4)
+		}
+	}
+
+	before := positions[len(positions)-2]
+	if san, err := before.SAN(game.Moves[4]); err != nil || san != "exd6" {
+		t.Errorf("SAN() = %q, %v, want \"exd6\"", san, err)
+	}
+	uci, err := before.ParseUCI("e5d6")
+	if err != nil || !uci.IsEnPassant || !uci.IsCapture {
+		t.Errorf("ParseUCI(\"e5d6\") = %+v, %v, want an en passant capture", uci, err)
+	}
+
+	t.Run("only immediately after the double step", func(t *testing.T) {
+		late, err := chessnote.ParseString("1. e4 a6 2. e5 d5 3. h3 h6 4. exd6 *")
+		if err != nil {
+			t.Fatalf("ParseString() failed: %v", err)
+		}
+		if _, err := late.Positions(); err == nil {
+			t.Error("Positions() expected an error for a late en passant capture, but got nil")
+		}
+	})
+
+	t.Run("exposing the king along the rank", func(t *testing.T) {
+		b, err := chessnote.ParseFEN("8/8/8/K2pP2r/8/8/8/4k3 w - d6 0 1")
+		if err != nil {
+			t.Fatalf("ParseFEN() failed: %v", err)
+		}
+		m := chessnote.Move{Piece: chessnote.Pawn, From: chessnote.Square{File: 4}, HasFromFile: true, To: d6, IsCapture: true}
+		if err := b.Apply(m); err == nil {
+			t.Error("Apply(exd6) expected an error for an en passant capture exposing the king, but got nil")
+		}
+	})
+}
+
+func TestBoardApplyDisambiguation(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		pgn  string
+		from chessnote.Square
+		to   chessnote.Square
+	}{
+		{"file hint", "1. Nf3 d5 2. Nc3 d4 3. Nb5 e5 4. Nbxd4 *", chessnote.Square{File: 1, Rank: 4}, chessnote.Square{File: 3, Rank: 3}},
+		{"a-file hint", "1. a4 h5 2. Ra3 h4 3. Nc3 h3 4. Nf3 e5 5. e3 d5 6. Bd3 c5 7. O-O c4 8. Rab3 *", chessnote.Square{File: 0, Rank: 2}, chessnote.Square{File: 1, Rank: 2}},
+		{"rank-1 hint on a shared file", "1. Nf3 a6 2. Nd4 a5 3. Nb5 h6 4. N1c3 *", chessnote.Square{File: 1, Rank: 0}, chessnote.Square{File: 2, Rank: 2}},
+		{"pawn capture from the a-file", "1. a4 b5 2. axb5 *", chessnote.Square{File: 0, Rank: 3}, chessnote.Square{File: 1, Rank: 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game, err := chessnote.ParseString(tt.pgn)
+			if err != nil {
+				t.Fatalf("ParseString() failed: %v", err)
+			}
+			positions, err := game.Positions()
+			if err != nil {
+				t.Fatalf("Positions() failed: %v", err)
+			}
+			before, after := positions[len(positions)-2], positions[len(positions)-1]
+			piece := before.PieceAt(tt.from)
+			if piece.IsEmpty() {
+				t.Fatalf("expected a piece on the origin square before the move")
+			}
+			if !after.PieceAt(tt.from).IsEmpty() {
+				t.Errorf("expected origin square to be empty after the move")
+			}
+			if got := after.PieceAt(tt.to); got != piece {
+				t.Errorf("got %+v on destination, want %+v", got, piece)
+			}
+		})
+	}
+}
+
+func TestMoveResolveFrom(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		fen     string
+		san     string
+		want    string // The resolved move, as written by Move.String.
+		wantErr bool
+	}{
+		{name: "unambiguous knight", fen: "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", san: "Nf3", want: "Ng1f3"},
+		{name: "pawn push", fen: "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", san: "e4", want: "e2e4"},
+		{name: "ambiguous knight", fen: "7k/8/8/8/2N1N3/8/8/4K3 w - - 0 1", san: "Nd2", wantErr: true},
+		{name: "ambiguous knight on the a-file", fen: "7k/8/8/8/8/N3N3/8/4K3 w - - 0 1", san: "Nc4", wantErr: true},
+		{name: "disambiguated knight", fen: "7k/8/8/8/2N1N3/8/8/4K3 w - - 0 1", san: "Ned2", want: "Ne4d2"},
+		{name: "ambiguity removed by a pin", fen: "4r2k/8/8/8/2N1N3/8/8/4K3 w - - 0 1", san: "Nd2", want: "Nc4d2"},
+		{name: "no piece can move there", fen: "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", san: "Ne4", wantErr: true},
+		{name: "en passant", fen: "4k3/8/8/3pP3/8/8/8/4K3 w - d6 0 1", san: "exd6", want: "e5xd6"},
+		{name: "queenside castling", fen: "r3k2r/8/8/8/8/8/8/R3K2R b KQkq - 0 1", san: "O-O-O", want: "O-O-O"},
+		{name: "castling blocked", fen: "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", san: "O-O", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := chessnote.ParseFEN(tt.fen)
+			if err != nil {
+				t.Fatalf("ParseFEN() failed: %v", err)
+			}
+			moves, err := chessnote.ParseMovetext(tt.san)
+			if err != nil {
+				t.Fatalf("ParseMovetext() failed: %v", err)
+			}
+			got, err := moves[0].ResolveFrom(b)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ResolveFrom() = %v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveFrom() failed: %v", err)
+			}
+			if got.String() != tt.want || !got.HasFromFile || !got.HasFromRank {
+				t.Errorf("ResolveFrom() = %+v (%s), want %s", got, got, tt.want)
+			}
+			if b.FEN() != tt.fen {
+				t.Errorf("expected the board to be unchanged, got %s", b.FEN())
+			}
+			if err := b.Apply(got); err != nil {
+				t.Errorf("Apply() of the resolved move failed: %v", err)
+			}
+		})
+	}
+
+	b, err := chessnote.ParseFEN("4k3/8/8/3pP3/8/8/8/4K3 w - d6 0 1")
+	if err != nil {
+		t.Fatalf("ParseFEN() failed: %v", err)
+	}
+	capture := chessnote.Move{Piece: chessnote.Pawn, From: chessnote.Square{File: 4}, HasFromFile: true, To: chessnote.Square{File: 3, Rank: 5}, IsCapture: true}
+	if got, err := capture.ResolveFrom(b); err != nil || !got.IsEnPassant || got.From != (chessnote.Square{File: 4, Rank: 4}) {
+		t.Errorf("ResolveFrom() = %+v, %v, want an en passant capture from e5", got, err)
+	}
+	castle := chessnote.Move{Piece: chessnote.King, IsKingsideCastle: true}
+	b, _ = chessnote.ParseFEN("r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1")
+	if got, err := castle.ResolveFrom(b); err != nil || got.From != (chessnote.Square{File: 4}) || got.To != (chessnote.Square{File: 6}) {
+		t.Errorf("ResolveFrom() = %+v, %v, want the king moving from e1 to g1", got, err)
+	}
+}
+
+func TestBoardIsInsufficientMaterial(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		fen  string
+		want bool
+	}{
+		{"bare kings", "7k/8/8/8/8/8/8/7K w - - 0 1", true},
+		{"lone knight", "7k/8/8/8/8/8/8/5N1K w - - 0 1", true},
+		{"bishops on the same colour", "4b2k/8/8/8/8/8/8/5B1K w - - 0 1", true},
+		{"bishops on opposite colours", "5b1k/8/8/8/8/8/8/5B1K w - - 0 1", false},
+		{"two knights", "7k/8/8/8/8/8/8/4NN1K w - - 0 1", false},
+		{"pawn", "7k/8/8/8/8/8/P7/7K w - - 0 1", false},
+		{"rook", "7k/8/8/8/8/8/8/R6K w - - 0 1", false},
+		{"starting position", "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := chessnote.ParseFEN(tt.fen)
+			if err != nil {
+				t.Fatalf("ParseFEN() failed: %v", err)
+			}
+			if got := b.IsInsufficientMaterial(); got != tt.want {
+				t.Errorf("IsInsufficientMaterial() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBoardIsCheck(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		fen  string
+		move chessnote.Move
+		want bool
+	}{
+		{
+			name: "quiet move",
+			fen:  "4k3/8/8/8/4N3/8/8/4R2K w - - 0 1",
+			move: chessnote.Move{Piece: chessnote.King, To: chessnote.Square{File: 6, Rank: 0}},
+			want: false,
+		},
+		{
+			name: "direct check",
+			fen:  "4k3/8/8/8/8/8/8/R6K w - - 0 1",
+			move: chessnote.Move{Piece: chessnote.Rook, To: chessnote.Square{File: 0, Rank: 7}},
+			want: true,
+		},
+		{
+			name: "discovered check",
+			fen:  "4k3/8/8/8/4N3/8/8/4R2K w - - 0 1",
+			move: chessnote.Move{Piece: chessnote.Knight, To: chessnote.Square{File: 2, Rank: 4}},
+			want: true,
+		},
+		{
+			name: "double check",
+			fen:  "4k3/8/8/8/4N3/8/8/4R2K w - - 0 1",
+			move: chessnote.Move{Piece: chessnote.Knight, To: chessnote.Square{File: 3, Rank: 5}},
+			want: true,
+		},
+		{
+			name: "pawn push without check",
+			fen:  "4k3/8/8/3P4/8/8/8/7K w - - 0 1",
+			move: chessnote.Move{Piece: chessnote.Pawn, To: chessnote.Square{File: 3, Rank: 5}},
+			want: false,
+		},
+		{
+			name: "pawn check against the black king",
+			fen:  "8/4k3/8/3P4/8/8/8/7K w - - 0 1",
+			move: chessnote.Move{Piece: chessnote.Pawn, To: chessnote.Square{File: 3, Rank: 5}},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := chessnote.ParseFEN(tt.fen)
+			if err != nil {
+				t.Fatalf("ParseFEN() error = %v", err)
+			}
+			if b.IsCheck() {
+				t.Fatalf("expected no check before the move")
+			}
+			if err := b.Apply(tt.move); err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+			if got := b.IsCheck(); got != tt.want {
+				t.Errorf("IsCheck() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGameCheckStatus(t *testing.T) {
+	t.Parallel()
+	// The discovered check on move 1 is deliberately written without "+".
+	pgn := `[FEN "4k3/8/8/8/4N3/8/8/4R2K w - - 0 1"]
+
+1. Nc5 Kf7 2. Nd7 Kg6 3. Re6+ *`
+	game, err := chessnote.ParseString(pgn)
+	if err != nil {
+		t.Fatalf("ParseString() failed: %v", err)
+	}
+	got, err := game.CheckStatus()
+	if err != nil {
+		t.Fatalf("CheckStatus() failed: %v", err)
+	}
+	want := []bool{true, false, false, false, true}
+	if len(got) != len(want) {
+		t.Fatalf("got %d statuses, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("ply %d: got check %t, want %t", i+1, got[i], want[i])
+		}
+	}
+}
+
+func TestGameEndsInCheckmateOrStalemate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name          string
+		pgn           string
+		wantCheckmate bool
+		wantStalemate bool
+	}{
+		{"annotated checkmate", operaGame, true, false},
+		{"unannotated checkmate", "1. f3 e5 2. g4 Qh4 0-1", true, false},
+		{"spurious mate annotation", "1. e4 e5 2. Qh5 Nc6 3. Qxf7# *", false, false},
+		{"unreplayable game falls back to the annotation", "1. e4 e5 2. Qxf7# 1-0", true, false},
+		{"stalemate", "[FEN \"7k/8/8/6Q1/8/8/8/7K w - - 0 1\"]\n\n1. Qg6 1/2-1/2", false, true},
+		{"unfinished game", "1. e4 e5 *", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game, err := chessnote.ParseString(tt.pgn)
+			if err != nil {
+				t.Fatalf("ParseString() failed: %v", err)
+			}
+			if got := game.EndsInCheckmate(); got != tt.wantCheckmate {
+				t.Errorf("EndsInCheckmate() = %t, want %t", got, tt.wantCheckmate)
+			}
+			if got := game.EndsInStalemate(); got != tt.wantStalemate {
+				t.Errorf("EndsInStalemate() = %t, want %t", got, tt.wantStalemate)
+			}
+		})
+	}
+}
+
+func TestGameForEachPosition(t *testing.T) {
+	t.Parallel()
+	game, err := chessnote.ParseString(operaGame)
+	if err != nil {
+		t.Fatalf("ParseString() failed: %v", err)
+	}
+	positions, err := game.Positions()
+	if err != nil {
+		t.Fatalf("Positions() failed: %v", err)
+	}
+
+	calls := 0
+	err = game.ForEachPosition(func(ply int, b *chessnote.Board) error {
+		if ply != calls {
+			t.Errorf("call %d: got ply %d", calls, ply)
+		}
+		if b.FEN() != positions[ply].FEN() {
+			t.Errorf("ply %d: got %s, want %s", ply, b.FEN(), positions[ply].FEN())
+		}
+		calls++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ForEachPosition() failed: %v", err)
+	}
+	if calls != len(game.Moves)+1 {
+		t.Errorf("got %d calls, want %d", calls, len(game.Moves)+1)
+	}
+
+	t.Run("callback error stops the replay", func(t *testing.T) {
+		stop := errors.New("stop")
+		calls := 0
+		err := game.ForEachPosition(func(ply int, b *chessnote.Board) error {
+			calls++
+			if ply == 4 {
+				return stop
+			}
+			return nil
+		})
+		if err != stop {
+			t.Errorf("ForEachPosition() error = %v, want %v", err, stop)
+		}
+		if calls != 5 {
+			t.Errorf("got %d calls, want 5", calls)
+		}
+	})
+
+	t.Run("illegal move", func(t *testing.T) {
+		illegal, err := chessnote.ParseString("1. e4 e5 2. Ke3 *")
+		if err != nil {
+			t.Fatalf("ParseString() failed: %v", err)
+		}
+		calls := 0
+		err = illegal.ForEachPosition(func(int, *chessnote.Board) error {
+			calls++
+			return nil
+		})
+		var replayErr *chessnote.ReplayError
+		if !errors.As(err, &replayErr) || replayErr.Ply != 3 {
+			t.Errorf("ForEachPosition() error = %v, want a *ReplayError at ply 3", err)
+		}
+		if calls != 3 {
+			t.Errorf("got %d calls, want 3", calls)
+		}
+	})
+}
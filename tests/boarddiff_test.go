@@ -0,0 +1,77 @@
+package chessnote_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/YashBhalodi/chessnote"
+)
+
+func TestBoardDiff(t *testing.T) {
+	t.Parallel()
+	wp := chessnote.Piece{Type: chessnote.Pawn, Color: chessnote.White}
+	bp := chessnote.Piece{Type: chessnote.Pawn, Color: chessnote.Black}
+	wk := chessnote.Piece{Type: chessnote.King, Color: chessnote.White}
+	wr := chessnote.Piece{Type: chessnote.Rook, Color: chessnote.White}
+	sq := func(name string) chessnote.Square {
+		s, err := chessnote.SquareFromAlgebraic(name)
+		if err != nil {
+			t.Fatalf("SquareFromAlgebraic(%q) failed: %v", name, err)
+		}
+		return s
+	}
+
+	tests := []struct {
+		name string
+		pgn  string
+		want []chessnote.SquareChange
+	}{
+		{
+			name: "capture",
+			pgn:  "1. e4 d5 2. exd5 *",
+			want: []chessnote.SquareChange{
+				{Square: sq("e4"), Before: wp},
+				{Square: sq("d5"), Before: bp, After: wp},
+			},
+		},
+		{
+			name: "castle",
+			pgn:  "1. e4 e5 2. Nf3 Nc6 3. Bc4 Bc5 4. O-O *",
+			want: []chessnote.SquareChange{
+				{Square: sq("e1"), Before: wk},
+				{Square: sq("f1"), After: wr},
+				{Square: sq("g1"), After: wk},
+				{Square: sq("h1"), Before: wr},
+			},
+		},
+		{
+			name: "en passant",
+			pgn:  "1. e4 a6 2. e5 d5 3. exd6 *",
+			want: []chessnote.SquareChange{
+				{Square: sq("d5"), Before: bp},
+				{Square: sq("e5"), Before: wp},
+				{Square: sq("d6"), After: wp},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game, err := chessnote.ParseString(tt.pgn)
+			if err != nil {
+				t.Fatalf("ParseString() failed: %v", err)
+			}
+			positions, err := game.Positions()
+			if err != nil {
+				t.Fatalf("Positions() failed: %v", err)
+			}
+			got := chessnote.BoardDiff(positions[len(positions)-2], positions[len(positions)-1])
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BoardDiff() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if got := chessnote.BoardDiff(chessnote.NewBoard(), chessnote.NewBoard()); got != nil {
+		t.Errorf("BoardDiff() of identical boards = %+v, want nil", got)
+	}
+}
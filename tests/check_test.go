@@ -0,0 +1,101 @@
+package chessnote_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/YashBhalodi/chessnote"
+)
+
+func TestBoardCheckType(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		fen  string
+		move chessnote.Move
+		want chessnote.CheckType
+	}{
+		{
+			name: "quiet move",
+			fen:  "4k3/8/8/8/4N3/8/8/4R2K w - - 0 1",
+			move: chessnote.Move{Piece: chessnote.King, To: chessnote.Square{File: 6, Rank: 0}},
+			want: chessnote.CheckNone,
+		},
+		{
+			name: "direct check",
+			fen:  "4k3/8/8/8/8/8/8/R6K w - - 0 1",
+			move: chessnote.Move{Piece: chessnote.Rook, To: chessnote.Square{File: 0, Rank: 7}},
+			want: chessnote.CheckSingle,
+		},
+		{
+			name: "knight uncovers the rook",
+			fen:  "4k3/8/8/8/4N3/8/8/4R2K w - - 0 1",
+			move: chessnote.Move{Piece: chessnote.Knight, To: chessnote.Square{File: 2, Rank: 4}},
+			want: chessnote.CheckDiscovered,
+		},
+		{
+			name: "bishop uncovers the queen",
+			fen:  "7k/8/8/8/3B4/8/8/Q6K w - - 0 1",
+			move: chessnote.Move{Piece: chessnote.Bishop, To: chessnote.Square{File: 1, Rank: 5}},
+			want: chessnote.CheckDiscovered,
+		},
+		{
+			name: "knight and rook double check",
+			fen:  "4k3/8/8/8/4N3/8/8/4R2K w - - 0 1",
+			move: chessnote.Move{Piece: chessnote.Knight, To: chessnote.Square{File: 3, Rank: 5}},
+			want: chessnote.CheckDouble,
+		},
+		{
+			name: "castling rook gives check",
+			fen:  "5k2/8/8/8/8/8/8/4K2R w K - 0 1",
+			move: chessnote.Move{IsKingsideCastle: true},
+			want: chessnote.CheckSingle,
+		},
+		{
+			name: "promoted piece gives check",
+			fen:  "1k6/4P3/8/8/8/8/8/4K3 w - - 0 1",
+			move: chessnote.Move{Piece: chessnote.Pawn, To: chessnote.Square{File: 4, Rank: 7}, Promotion: chessnote.Queen},
+			want: chessnote.CheckSingle,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := chessnote.ParseFEN(tt.fen)
+			if err != nil {
+				t.Fatalf("ParseFEN() error = %v", err)
+			}
+			got, err := b.CheckType(tt.move)
+			if err != nil {
+				t.Fatalf("CheckType() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("CheckType() = %v, want %v", got, tt.want)
+			}
+			if b.FEN() != tt.fen {
+				t.Errorf("CheckType() modified the board")
+			}
+		})
+	}
+}
+
+func TestGameCheckTypes(t *testing.T) {
+	t.Parallel()
+	pgn := `[FEN "4k3/8/8/8/4N3/8/8/4R2K w - - 0 1"]
+
+1. Nc5+ Kf7 2. Nd7 Kg6 3. Re6+ Kf5 4. Nf8 *`
+	game, err := chessnote.ParseString(pgn)
+	if err != nil {
+		t.Fatalf("ParseString() failed: %v", err)
+	}
+	got, err := game.CheckTypes()
+	if err != nil {
+		t.Fatalf("CheckTypes() failed: %v", err)
+	}
+	want := []chessnote.CheckType{
+		chessnote.CheckDiscovered, chessnote.CheckNone, chessnote.CheckNone, chessnote.CheckNone,
+		chessnote.CheckSingle, chessnote.CheckNone, chessnote.CheckNone,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CheckTypes() = %v, want %v", got, want)
+	}
+}
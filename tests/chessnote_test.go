@@ -1,7 +1,9 @@
 package chessnote_test
 
 import (
+	"os"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/YashBhalodi/chessnote"
@@ -38,6 +40,45 @@ func TestParseTagPairs(t *testing.T) {
 	}
 }
 
+func TestParseTagsWithoutBlankLine(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		pgn  string
+	}{
+		{"next line", "[Event \"Casual\"]\n[Result \"1-0\"]\n1. e4 e5 2. Qh5 {Early} Nc6 1-0"},
+		{"same line", "[Event \"Casual\"] [Result \"1-0\"] 1. e4 e5 2. Qh5 {Early} Nc6 1-0"},
+		{"no spaces", "[Event \"Casual\"][Result \"1-0\"]1. e4 e5 2. Qh5 {Early} Nc6 1-0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game, err := chessnote.ParseString(tt.pgn)
+			if err != nil {
+				t.Fatalf("ParseString() failed: %v", err)
+			}
+			if want := map[string]string{"Event": "Casual", "Result": "1-0"}; !reflect.DeepEqual(game.Tags, want) {
+				t.Errorf("got tags %v, want %v", game.Tags, want)
+			}
+			if len(game.Moves) != 4 || game.Result != "1-0" {
+				t.Errorf("got %d moves and result %q, want 4 moves and %q", len(game.Moves), game.Result, "1-0")
+			}
+			if want := []string{"Early"}; !reflect.DeepEqual(game.Moves[2].Comments, want) {
+				t.Errorf("got comments %q, want %q", game.Moves[2].Comments, want)
+			}
+
+			games := chessnote.SplitMultiGame(tt.pgn + "\n" + tt.pgn)
+			if len(games) != 2 {
+				t.Fatalf("SplitMultiGame() got %d games, want 2", len(games))
+			}
+			for _, g := range games {
+				if _, err := chessnote.ParseString(g); err != nil {
+					t.Errorf("ParseString() of a split game failed: %v", err)
+				}
+			}
+		})
+	}
+}
+
 func TestParseMoves(t *testing.T) {
 	t.Parallel()
 	testCases := []struct {
@@ -76,28 +117,33 @@ func TestParseMoves(t *testing.T) {
 		{
 			name: "pawn capture",
 			pgn:  "1. exd5 *",
-			want: chessnote.Move{Piece: chessnote.Pawn, From: chessnote.Square{File: 4}, To: chessnote.Square{File: 3, Rank: 4}, IsCapture: true},
+			want: chessnote.Move{Piece: chessnote.Pawn, From: chessnote.Square{File: 4}, HasFromFile: true, To: chessnote.Square{File: 3, Rank: 4}, IsCapture: true},
 		},
 		// Disambiguation
 		{
 			name: "file disambiguation",
 			pgn:  "1. Rdf8 *",
-			want: chessnote.Move{Piece: chessnote.Rook, From: chessnote.Square{File: 3}, To: chessnote.Square{File: 5, Rank: 7}},
+			want: chessnote.Move{Piece: chessnote.Rook, From: chessnote.Square{File: 3}, HasFromFile: true, To: chessnote.Square{File: 5, Rank: 7}},
 		},
 		{
 			name: "rank disambiguation",
 			pgn:  "1. N1c3 *",
-			want: chessnote.Move{Piece: chessnote.Knight, From: chessnote.Square{Rank: 0}, To: chessnote.Square{File: 2, Rank: 2}},
+			want: chessnote.Move{Piece: chessnote.Knight, From: chessnote.Square{Rank: 0}, HasFromRank: true, To: chessnote.Square{File: 2, Rank: 2}},
 		},
 		{
 			name: "file disambiguation with capture",
 			pgn:  "1. Rdxf8 *",
-			want: chessnote.Move{Piece: chessnote.Rook, From: chessnote.Square{File: 3}, To: chessnote.Square{File: 5, Rank: 7}, IsCapture: true},
+			want: chessnote.Move{Piece: chessnote.Rook, From: chessnote.Square{File: 3}, HasFromFile: true, To: chessnote.Square{File: 5, Rank: 7}, IsCapture: true},
 		},
 		{
 			name: "rank disambiguation with capture",
 			pgn:  "1. N1xc3 *",
-			want: chessnote.Move{Piece: chessnote.Knight, From: chessnote.Square{Rank: 0}, To: chessnote.Square{File: 2, Rank: 2}, IsCapture: true},
+			want: chessnote.Move{Piece: chessnote.Knight, From: chessnote.Square{Rank: 0}, HasFromRank: true, To: chessnote.Square{File: 2, Rank: 2}, IsCapture: true},
+		},
+		{
+			name: "file and rank disambiguation",
+			pgn:  "1. Qh4xe1 *",
+			want: chessnote.Move{Piece: chessnote.Queen, From: chessnote.Square{File: 7, Rank: 3}, HasFromFile: true, HasFromRank: true, To: chessnote.Square{File: 4}, IsCapture: true},
 		},
 		// Promotion
 		{
@@ -108,13 +154,38 @@ func TestParseMoves(t *testing.T) {
 		{
 			name: "promotion with capture",
 			pgn:  "1. exd8=R *",
-			want: chessnote.Move{Piece: chessnote.Pawn, From: chessnote.Square{File: 4}, To: chessnote.Square{File: 3, Rank: 7}, IsCapture: true, Promotion: chessnote.Rook},
+			want: chessnote.Move{Piece: chessnote.Pawn, From: chessnote.Square{File: 4}, HasFromFile: true, To: chessnote.Square{File: 3, Rank: 7}, IsCapture: true, Promotion: chessnote.Rook},
 		},
 		{
 			name: "promotion with check",
 			pgn:  "1. e8=Q+ *",
 			want: chessnote.Move{Piece: chessnote.Pawn, To: chessnote.Square{File: 4, Rank: 7}, Promotion: chessnote.Queen, IsCheck: true},
 		},
+		{
+			name: "promotion without equals sign",
+			pgn:  "1. e8Q *",
+			want: chessnote.Move{Piece: chessnote.Pawn, To: chessnote.Square{File: 4, Rank: 7}, Promotion: chessnote.Queen},
+		},
+		{
+			name: "promotion with capture without equals sign",
+			pgn:  "1. exd8N *",
+			want: chessnote.Move{Piece: chessnote.Pawn, From: chessnote.Square{File: 4}, HasFromFile: true, To: chessnote.Square{File: 3, Rank: 7}, IsCapture: true, Promotion: chessnote.Knight},
+		},
+		{
+			name: "promotion with check without equals sign",
+			pgn:  "1. e8Q+ *",
+			want: chessnote.Move{Piece: chessnote.Pawn, To: chessnote.Square{File: 4, Rank: 7}, Promotion: chessnote.Queen, IsCheck: true},
+		},
+		{
+			name: "black promotion without equals sign",
+			pgn:  "1... a1R# *",
+			want: chessnote.Move{Piece: chessnote.Pawn, To: chessnote.Square{File: 0, Rank: 0}, Promotion: chessnote.Rook, IsMate: true},
+		},
+		{
+			name: "queen move to the back rank",
+			pgn:  "1. Qe8 *",
+			want: chessnote.Move{Piece: chessnote.Queen, To: chessnote.Square{File: 4, Rank: 7}},
+		},
 		// Castling
 		{
 			name: "kingside castle",
@@ -170,6 +241,100 @@ func TestParseMoves(t *testing.T) {
 	}
 }
 
+func TestParseLongAlgebraic(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		pgn  string
+		want chessnote.Move
+	}{
+		{"pawn push", "1. e2-e4 *", chessnote.Move{Piece: chessnote.Pawn, To: chessnote.Square{File: 4, Rank: 3}}},
+		{"piece move", "1. Ng1-f3 *", chessnote.Move{Piece: chessnote.Knight, From: chessnote.Square{File: 6, Rank: 0}, HasFromFile: true, HasFromRank: true, To: chessnote.Square{File: 5, Rank: 2}}},
+		{"pawn capture", "1. e4xd5 *", chessnote.Move{Piece: chessnote.Pawn, From: chessnote.Square{File: 4}, HasFromFile: true, To: chessnote.Square{File: 3, Rank: 4}, IsCapture: true}},
+		{"capture with check", "1. Bf1xb5+ *", chessnote.Move{Piece: chessnote.Bishop, From: chessnote.Square{File: 5, Rank: 0}, HasFromFile: true, HasFromRank: true, To: chessnote.Square{File: 1, Rank: 4}, IsCapture: true, IsCheck: true}},
+		{"promotion", "1. e7-e8=Q *", chessnote.Move{Piece: chessnote.Pawn, To: chessnote.Square{File: 4, Rank: 7}, Promotion: chessnote.Queen}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game, err := chessnote.ParseString(tt.pgn)
+			if err != nil {
+				t.Fatalf("ParseString() failed: %v", err)
+			}
+			if !reflect.DeepEqual(game.Moves[0], tt.want) {
+				t.Errorf("got move %+v, want %+v", game.Moves[0], tt.want)
+			}
+		})
+	}
+
+	t.Run("replays like SAN", func(t *testing.T) {
+		game, err := chessnote.ParseString("1. e2-e4 d7-d5 2. e4xd5 Qd8xd5 3. Nb1-c3 *")
+		if err != nil {
+			t.Fatalf("ParseString() failed: %v", err)
+		}
+		if _, err := game.Positions(); err != nil {
+			t.Errorf("Positions() failed: %v", err)
+		}
+	})
+
+	for _, pgn := range []string{"1. e2-e9 *", "1. e2=e4 *", "1. Ng1-f *", "1. e2-d4 *", "1. e4xe5 *", "1. e4xb5 *"} {
+		if _, err := chessnote.ParseString(pgn); err == nil {
+			t.Errorf("ParseString(%q) expected an error, but got nil", pgn)
+		}
+	}
+}
+
+func TestParseCastlingWithSuffixes(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		raw           string
+		wantKingside  bool
+		wantCheck     bool
+		wantMate      bool
+		wantNAGs      []int
+		wantCanonical string
+	}{
+		{"O-O", true, false, false, nil, "O-O"},
+		{"O-O+", true, true, false, nil, "O-O+"},
+		{"O-O#", true, false, true, nil, "O-O#"},
+		{"O-O!", true, false, false, []int{1}, "O-O"},
+		{"O-O!?", true, false, false, []int{5}, "O-O"},
+		{"O-O+!", true, true, false, []int{1}, "O-O+"},
+		{"O-O-O", false, false, false, nil, "O-O-O"},
+		{"O-O-O#", false, false, true, nil, "O-O-O#"},
+		{"O-O-O+??", false, true, false, []int{4}, "O-O-O+"},
+		{"0-0", true, false, false, nil, "O-O"},
+		{"0-0+", true, true, false, nil, "O-O+"},
+		{"0-0!?", true, false, false, []int{5}, "O-O"},
+		{"0-0-0", false, false, false, nil, "O-O-O"},
+		{"0-0-0#", false, false, true, nil, "O-O-O#"},
+		{"0-0-0+?!", false, true, false, []int{6}, "O-O-O+"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			game, err := chessnote.ParseString("1. " + tt.raw + " *")
+			if err != nil {
+				t.Fatalf("ParseString() failed: %v", err)
+			}
+			if len(game.Moves) != 1 {
+				t.Fatalf("expected 1 move, got %d", len(game.Moves))
+			}
+			m := game.Moves[0]
+			if m.Piece != chessnote.King || m.IsKingsideCastle != tt.wantKingside || m.IsQueensideCastle == tt.wantKingside {
+				t.Errorf("got %+v, want a castling move with kingside = %v", m, tt.wantKingside)
+			}
+			if m.IsCheck != tt.wantCheck || m.IsMate != tt.wantMate {
+				t.Errorf("got check = %v, mate = %v, want %v, %v", m.IsCheck, m.IsMate, tt.wantCheck, tt.wantMate)
+			}
+			if !reflect.DeepEqual(m.NAGs, tt.wantNAGs) {
+				t.Errorf("got NAGs %v, want %v", m.NAGs, tt.wantNAGs)
+			}
+			if got := m.String(); got != tt.wantCanonical {
+				t.Errorf("String() = %q, want %q", got, tt.wantCanonical)
+			}
+		})
+	}
+}
+
 func TestParseWithComments(t *testing.T) {
 	t.Parallel()
 	pgn := `
@@ -198,8 +363,13 @@ func TestParseWithComments(t *testing.T) {
 		t.Errorf("got tags %v, want %v", game.Tags, expectedTags)
 	}
 
+	expectedComments := []string{"This is a comment at the start."}
+	if !reflect.DeepEqual(game.Comments, expectedComments) {
+		t.Errorf("got game comments %q, want %q", game.Comments, expectedComments)
+	}
+
 	expectedMoves := []chessnote.Move{
-		{Piece: chessnote.Pawn, To: chessnote.Square{File: 4, Rank: 3}},
+		{Piece: chessnote.Pawn, To: chessnote.Square{File: 4, Rank: 3}, Comments: []string{"This is a move comment", "This is a comment between moves."}},
 		{Piece: chessnote.Pawn, To: chessnote.Square{File: 4, Rank: 4}},
 		{Piece: chessnote.Knight, To: chessnote.Square{File: 5, Rank: 2}},
 		{Piece: chessnote.Knight, To: chessnote.Square{File: 2, Rank: 5}},
@@ -247,6 +417,37 @@ func TestParseWithRAV(t *testing.T) {
 	}
 }
 
+func TestParseCommentOnlyRAV(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		pgn          string
+		wantComments []string
+	}{
+		{"brace comment", "1. e4 ({A note}) e5 *", []string{"A note"}},
+		{"line comment", "1. e4 (; A note\n) e5 *", []string{"A note"}},
+		{"after a move comment", "1. e4 {Best} ({Also good}) e5 *", []string{"Best", "Also good"}},
+		{"no content", "1. e4 () e5 *", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game, err := chessnote.ParseString(tt.pgn)
+			if err != nil {
+				t.Fatalf("ParseString() failed: %v", err)
+			}
+			if len(game.Moves) != 2 {
+				t.Fatalf("expected 2 moves, got %d", len(game.Moves))
+			}
+			if n := len(game.Moves[0].Variations); n != 0 {
+				t.Errorf("expected no variations, got %d", n)
+			}
+			if !reflect.DeepEqual(game.Moves[0].Comments, tt.wantComments) {
+				t.Errorf("got comments %q, want %q", game.Moves[0].Comments, tt.wantComments)
+			}
+		})
+	}
+}
+
 func TestParseStrictness(t *testing.T) {
 	t.Parallel()
 	pgnWithoutResult := "1. e4 e5" // No result token
@@ -270,6 +471,244 @@ func TestParseStrictness(t *testing.T) {
 			t.Errorf("expected 2 moves, got %d", len(game.Moves))
 		}
 	})
+
+	t.Run("lax mode succeeds with a comment before the end of input", func(t *testing.T) {
+		for _, pgn := range []string{"1. e4 e5 {end}", "1. e4 e5 {end}\n", "1. e4 e5 ; end"} {
+			game, err := chessnote.ParseString(pgn, chessnote.WithLaxParsing())
+			if err != nil {
+				t.Fatalf("ParseString(%q) failed: %v", pgn, err)
+			}
+			if len(game.Moves) != 2 {
+				t.Fatalf("%q: expected 2 moves, got %d", pgn, len(game.Moves))
+			}
+			if want := []string{"end"}; !reflect.DeepEqual(game.Moves[1].Comments, want) {
+				t.Errorf("%q: got comments %q on the last move, want %q", pgn, game.Moves[1].Comments, want)
+			}
+			if game.Result != "" || game.ResultComments != nil {
+				t.Errorf("%q: got result %q and result comments %q, want neither", pgn, game.Result, game.ResultComments)
+			}
+		}
+	})
+
+	t.Run("strict mode fails with a comment before the end of input", func(t *testing.T) {
+		if _, err := chessnote.ParseString("1. e4 e5 {end}"); err == nil {
+			t.Error("expected an error in strict mode for PGN without a result, but got nil")
+		}
+	})
+}
+
+func TestParseResultGluedToMove(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		pgn        string
+		wantResult string
+		wantLast   string
+	}{
+		{"1. e4 e5 2. Qh5 Nc6 3. Bc4 Nf6 4. Qxf7#1-0", "1-0", "Qxf7#"},
+		{"1. f3 e5 2. g4 Qh4#0-1", "0-1", "Qh4#"},
+		{"1. e4 e5 2. Nf31/2-1/2", "1/2-1/2", "Nf3"},
+		{"1. e4 e5 2. Bc4 Nc6 3. Qh5 Nf6 4. Qxf7+1-0", "1-0", "Qxf7+"},
+	}
+	for _, tt := range tests {
+		game, err := chessnote.ParseString(tt.pgn)
+		if err != nil {
+			t.Fatalf("ParseString(%q) failed: %v", tt.pgn, err)
+		}
+		if game.Result != tt.wantResult {
+			t.Errorf("%q: got result %q, want %q", tt.pgn, game.Result, tt.wantResult)
+		}
+		if got := game.Moves[len(game.Moves)-1].String(); got != tt.wantLast {
+			t.Errorf("%q: got last move %s, want %s", tt.pgn, got, tt.wantLast)
+		}
+	}
+}
+
+func TestParseStrayClosingDelimiters(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		pgn  string
+		want string
+	}{
+		{"leading parenthesis", ") 1. e4 *", "line 1, column 1: unexpected ')' before the game's movetext: no variation is open"},
+		{"leading bracket", "\n  ] 1. e4 *", "line 2, column 3: unexpected ']' outside a tag pair"},
+		{"bracket after tags", "[Event \"E\"]\n] 1. e4 *", "line 2, column 1: unexpected ']' outside a tag pair"},
+		{"other token", "{Intro} . 1. e4 *", "line 1, column 9: unexpected token at start of game"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := chessnote.ParseString(tt.pgn)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ParseString(%q) error = %v, want it to contain %q", tt.pgn, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRequireDecisiveOrDraw(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		pgn     string
+		opts    []chessnote.ParserOption
+		wantErr bool
+	}{
+		{"in progress rejected", "1. e4 e5 *", nil, true},
+		{"in progress rejected in lax mode", "1. e4 e5 *", []chessnote.ParserOption{chessnote.WithLaxParsing()}, true},
+		{"white wins", "1. e4 e5 1-0", nil, false},
+		{"black wins", "1. e4 e5 0-1", nil, false},
+		{"draw", "1. e4 e5 1/2-1/2", nil, false},
+		{"missing result in strict mode", "1. e4 e5", nil, true},
+		{"missing result in lax mode", "1. e4 e5", []chessnote.ParserOption{chessnote.WithLaxParsing()}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]chessnote.ParserOption{chessnote.WithRequireDecisiveOrDraw()}, tt.opts...)
+			_, err := chessnote.ParseString(tt.pgn, opts...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseString(%q) error = %v, wantErr %v", tt.pgn, err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := chessnote.ParseString("1. e4 e5 *"); err != nil {
+		t.Errorf("without the option, ParseString() failed on an in-progress game: %v", err)
+	}
+}
+
+func TestParseBytes(t *testing.T) {
+	t.Parallel()
+	inputs := []string{
+		operaGame,
+		"\uFEFF[Event \"Caf\u00e9\"]\n\n1. e4 {\u00e9chec} e5!? (1... c5) 2. Nf3 *",
+		"%escape\r\n1. d4 ; line comment\n d5 1/2-1/2 {Agreed}",
+	}
+	for _, input := range inputs {
+		want, err := chessnote.ParseString(input)
+		if err != nil {
+			t.Fatalf("ParseString() failed: %v", err)
+		}
+		got, err := chessnote.ParseBytes([]byte(input))
+		if err != nil {
+			t.Fatalf("ParseBytes() failed: %v", err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("ParseBytes(%q) = %+v, want %+v", input, got, want)
+		}
+	}
+
+	if _, err := chessnote.ParseBytes([]byte("1. e4 e9 *")); err == nil {
+		t.Error("ParseBytes() expected an error for an invalid move, but got nil")
+	}
+}
+
+func TestParseHeaderlessSnippets(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		pgn       string
+		wantMoves int
+	}{
+		{"moves only", "1. e4 e5 2. Nf3", 3},
+		{"ends with Black's move", "1. d4 Nf6 2. c4 e6", 4},
+		{"with comments and variations", "1. e4 {Best} e5 (1... c5) 2. Nf3", 3},
+		{"with a result", "1. e4 e5 1/2-1/2", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game, err := chessnote.ParseString(tt.pgn, chessnote.WithLaxParsing())
+			if err != nil {
+				t.Fatalf("ParseString() failed: %v", err)
+			}
+			if len(game.Tags) != 0 {
+				t.Errorf("expected no tags, got %v", game.Tags)
+			}
+			if len(game.Moves) != tt.wantMoves {
+				t.Errorf("got %d moves from ParseString, want %d", len(game.Moves), tt.wantMoves)
+			}
+
+			moves, err := chessnote.ParseMovetext(tt.pgn)
+			if err != nil {
+				t.Fatalf("ParseMovetext() failed: %v", err)
+			}
+			if !reflect.DeepEqual(moves, game.Moves) {
+				t.Errorf("ParseMovetext() = %+v, want %+v", moves, game.Moves)
+			}
+		})
+	}
+}
+
+func TestParseMovetextErrors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		pgn  string
+	}{
+		{"tag section", "[Event \"Test\"]\n1. e4 *"},
+		{"two games", "1. e4 * 1. d4 *"},
+		{"invalid move", "1. e4 e9"},
+		{"promotion to a king without equals sign", "1. e8K"},
+		{"promotion letter after a piece move", "1. Ke8Q"},
+		{"promotion letter before the back rank", "1. e7Q"},
+		{"pawn push naming another file", "1. bc4 *"},
+		{"pawn push naming its own file", "1. ee4 *"},
+		{"pawn push naming a rank", "1. 2e4 *"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := chessnote.ParseMovetext(tt.pgn); err == nil {
+				t.Errorf("ParseMovetext(%q) expected an error, but got nil", tt.pgn)
+			}
+		})
+	}
+
+	moves, err := chessnote.ParseMovetext("")
+	if err != nil || len(moves) != 0 {
+		t.Errorf("ParseMovetext(\"\") = %v, %v, want no moves and no error", moves, err)
+	}
+}
+
+func TestParseSplitMovesLax(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		pgn  string
+		want []string
+	}{
+		{"pawn move split before rank", "1. e 4 e5 *", []string{"e4", "e5"}},
+		{"piece split from square", "1. e4 e5 2. N f3 *", []string{"e4", "e5", "Nf3"}},
+		{"split after a comment", "1. e4 {Open} e 5 *", []string{"e4", "e5"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game, err := chessnote.ParseString(tt.pgn, chessnote.WithLaxParsing())
+			if err != nil {
+				t.Fatalf("ParseString() failed: %v", err)
+			}
+			var got []string
+			for _, m := range game.Moves {
+				got = append(got, m.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got moves %v, want %v", got, tt.want)
+			}
+
+			if _, err := chessnote.ParseString(tt.pgn); err == nil {
+				t.Errorf("ParseString(%q) in strict mode expected an error, but got nil", tt.pgn)
+			}
+		})
+	}
+
+	invalid := []string{
+		"1. e4 e 9 *",  // The joined move is still invalid.
+		"1. e4 Z e5 *", // The joined move would be "Ze5".
+		"1. e4 e 1-0",  // A result is never joined.
+	}
+	for _, pgn := range invalid {
+		if _, err := chessnote.ParseString(pgn, chessnote.WithLaxParsing()); err == nil {
+			t.Errorf("ParseString(%q) expected an error, but got nil", pgn)
+		}
+	}
 }
 
 func TestParseWithNAGs(t *testing.T) {
@@ -338,3 +777,734 @@ func TestNewSquare(t *testing.T) {
 		})
 	}
 }
+
+func TestParseWithGlyphs(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name string
+		pgn  string
+		want [][]int
+	}{
+		{"single glyphs", "1. e4! e5? *", [][]int{{1}, {2}}},
+		{"double glyphs", "1. e4!! e5?? *", [][]int{{3}, {4}}},
+		{"mixed glyphs", "1. e4!? e5?! *", [][]int{{5}, {6}}},
+		{"glyph before explicit NAG", "1. e4 Qh5?? $10 *", [][]int{nil, {4, 10}}},
+		{"explicit NAG before glyph", "1. e4 $10 ?? e5 *", [][]int{{10, 4}, nil}},
+		{"glyph after mate suffix", "1. f3 e5 2. g4 Qh4#!! 0-1", [][]int{nil, nil, nil, {3}}},
+		{"glyph after castling", "1. e4 e5 2. Nf3 Nc6 3. Bc4 Bc5 4. O-O!? *", [][]int{nil, nil, nil, nil, nil, nil, {5}}},
+		{"glyph before move number", "1. e4!2. d4 *", [][]int{{1}, nil}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			game, err := chessnote.ParseString(tc.pgn)
+			if err != nil {
+				t.Fatalf("ParseString() failed: %v", err)
+			}
+			if len(game.Moves) != len(tc.want) {
+				t.Fatalf("expected %d moves, got %d", len(tc.want), len(game.Moves))
+			}
+			for i, want := range tc.want {
+				if !reflect.DeepEqual(game.Moves[i].NAGs, want) {
+					t.Errorf("move %d: got NAGs %v, want %v", i+1, game.Moves[i].NAGs, want)
+				}
+			}
+		})
+	}
+
+	t.Run("invalid glyph", func(t *testing.T) {
+		if _, err := chessnote.ParseString("1. e4!!! *"); err == nil {
+			t.Error("expected an error for an invalid glyph, but got nil")
+		}
+	})
+}
+
+func TestParseNullAnnotation(t *testing.T) {
+	t.Parallel()
+	game, err := chessnote.ParseString("1. e4 $0 *")
+	if err != nil {
+		t.Fatalf("ParseString() failed: %v", err)
+	}
+	if got := game.Moves[0].NAGs; !reflect.DeepEqual(got, []int{0}) {
+		t.Errorf("got NAGs %v, want [0]", got)
+	}
+	if got := game.Moves[0].PrimaryAnnotation(); got != 0 {
+		t.Errorf("PrimaryAnnotation() = %d, want 0", got)
+	}
+	if errs := game.Validate(); errs != nil {
+		t.Errorf("Validate() = %v, want no problems", errs)
+	}
+	if got := game.ToPGN(); !strings.Contains(got, "1. e4 $0 *") {
+		t.Errorf("ToPGN() = %q, want it to keep the null annotation", got)
+	}
+}
+
+func TestNAGSymbol(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		nag  int
+		want string
+	}{
+		{0, ""},
+		{1, "!"},
+		{2, "?"},
+		{3, "!!"},
+		{4, "??"},
+		{5, "!?"},
+		{6, "?!"},
+		{7, ""},
+		{255, ""},
+	}
+
+	for _, tc := range tests {
+		if got := chessnote.NAGSymbol(tc.nag); got != tc.want {
+			t.Errorf("NAGSymbol(%d) = %q, want %q", tc.nag, got, tc.want)
+		}
+	}
+}
+
+func TestMovePrimaryAnnotation(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		nags []int
+		want int
+	}{
+		{"no NAGs", nil, 0},
+		{"only positional NAGs", []int{10, 18}, 0},
+		{"blunder", []int{4}, 4},
+		{"quality NAG after positional NAG", []int{10, 4}, 4},
+		{"first quality NAG wins", []int{1, 6}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := chessnote.Move{NAGs: tt.nags}
+			if got := m.PrimaryAnnotation(); got != tt.want {
+				t.Errorf("PrimaryAnnotation() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoveCategories(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		san           string
+		wantPawn      bool
+		wantPromotion bool
+		wantCastle    bool
+	}{
+		{"e4", true, false, false},
+		{"exd5", true, false, false},
+		{"e8=Q", true, true, false},
+		{"fxg1=N+", true, true, false},
+		{"Nf3", false, false, false},
+		{"O-O", false, false, true},
+		{"O-O-O+", false, false, true},
+	}
+	for _, tt := range tests {
+		game, err := chessnote.ParseString("1. "+tt.san+" *", chessnote.WithLaxParsing())
+		if err != nil {
+			t.Fatalf("ParseString() failed: %v", err)
+		}
+		m := game.Moves[0]
+		if got := m.IsPawnMove(); got != tt.wantPawn {
+			t.Errorf("%s: IsPawnMove() = %v, want %v", tt.san, got, tt.wantPawn)
+		}
+		if got := m.IsPromotion(); got != tt.wantPromotion {
+			t.Errorf("%s: IsPromotion() = %v, want %v", tt.san, got, tt.wantPromotion)
+		}
+		if got := m.IsCastle(); got != tt.wantCastle {
+			t.Errorf("%s: IsCastle() = %v, want %v", tt.san, got, tt.wantCastle)
+		}
+	}
+
+	var zero chessnote.Move
+	if !zero.IsPawnMove() || zero.IsPromotion() || zero.IsCastle() {
+		t.Errorf("zero Move: got IsPawnMove %v, IsPromotion %v, IsCastle %v, want true, false, false",
+			zero.IsPawnMove(), zero.IsPromotion(), zero.IsCastle())
+	}
+}
+
+func TestParseTrailingContent(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name         string
+		pgn          string
+		opts         []chessnote.ParserOption
+		wantMoves    int
+		wantTrailing bool
+	}{
+		{
+			name:         "single game",
+			pgn:          "[Event \"1\"]\n1. e4 e5 *\n",
+			wantMoves:    2,
+			wantTrailing: false,
+		},
+		{
+			name:         "two concatenated games",
+			pgn:          "[Event \"1\"]\n1. e4 e5 1-0\n\n[Event \"2\"]\n1. d4 d5 0-1\n",
+			wantMoves:    2,
+			wantTrailing: true,
+		},
+		{
+			name:         "two concatenated games without a result on the first",
+			pgn:          "[Event \"1\"]\n1. e4 e5\n\n[Event \"2\"]\n1. d4 d5 0-1\n",
+			opts:         []chessnote.ParserOption{chessnote.WithLaxParsing()},
+			wantMoves:    2,
+			wantTrailing: true,
+		},
+		{
+			name:         "game without a result at end of input",
+			pgn:          "[Event \"1\"]\n1. e4 e5",
+			opts:         []chessnote.ParserOption{chessnote.WithLaxParsing()},
+			wantMoves:    2,
+			wantTrailing: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			game, err := chessnote.ParseString(tc.pgn, tc.opts...)
+			if err != nil {
+				t.Fatalf("ParseString() failed: %v", err)
+			}
+			if len(game.Moves) != tc.wantMoves {
+				t.Errorf("expected %d moves, got %d", tc.wantMoves, len(game.Moves))
+			}
+			if game.Trailing != tc.wantTrailing {
+				t.Errorf("got Trailing = %t, want %t", game.Trailing, tc.wantTrailing)
+			}
+		})
+	}
+}
+
+func TestParserParseRepeatedly(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		name        string
+		pgn         string
+		wantResults []string
+	}{
+		{
+			name:        "games with tags",
+			pgn:         "[Event \"1\"]\n1. e4 e5 1-0\n\n[Event \"2\"]\n1. d4 d5 0-1\n",
+			wantResults: []string{"1-0", "0-1"},
+		},
+		{
+			name:        "movetext separated only by the result",
+			pgn:         "1. e4 e5 1-0 1. d4 d5 0-1",
+			wantResults: []string{"1-0", "0-1"},
+		},
+		{
+			name:        "single game",
+			pgn:         "1. e4 e5 1/2-1/2\n",
+			wantResults: []string{"1/2-1/2"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := chessnote.NewParser(strings.NewReader(tc.pgn))
+			var results []string
+			for p.More() {
+				game, err := p.Parse()
+				if err != nil {
+					t.Fatalf("Parse() failed: %v", err)
+				}
+				if len(game.Moves) != 2 {
+					t.Errorf("game %d: expected 2 moves, got %d", len(results)+1, len(game.Moves))
+				}
+				results = append(results, game.Result)
+			}
+			if !reflect.DeepEqual(results, tc.wantResults) {
+				t.Errorf("got results %q, want %q", results, tc.wantResults)
+			}
+		})
+	}
+
+	t.Run("empty input", func(t *testing.T) {
+		if p := chessnote.NewParser(strings.NewReader(" \n")); p.More() {
+			t.Error("More() = true for empty input, want false")
+		}
+	})
+}
+
+func TestParseUTF8TagValues(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"acute accent", "Réti, Richard"},
+		{"hungarian", "Polgár, Judit"},
+		{"cyrillic", "Карпов, Анатолий"},
+		{"cjk", "侯逸凡"},
+		{"emoji", "Blitz ♞ Arena 🏆"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pgn := `[White "` + tt.value + `"]` + "\n" + `[Black "Ljubojević, Ljubomir"]` + "\n\n1. e4 *"
+			game, err := chessnote.ParseString(pgn)
+			if err != nil {
+				t.Fatalf("ParseString() failed: %v", err)
+			}
+			if game.Tags["White"] != tt.value {
+				t.Errorf("got White tag %q, want %q", game.Tags["White"], tt.value)
+			}
+			if game.Tags["Black"] != "Ljubojević, Ljubomir" {
+				t.Errorf("got Black tag %q, want %q", game.Tags["Black"], "Ljubojević, Ljubomir")
+			}
+		})
+	}
+}
+
+func TestParseCommentPlacement(t *testing.T) {
+	t.Parallel()
+	pgn := `{Intro} 1. e4 {Best by test} e5 (1... c5 {Sicilian}) ({Or} 1... e6) 2. Nf3 *`
+	game, err := chessnote.ParseString(pgn)
+	if err != nil {
+		t.Fatalf("ParseString() failed: %v", err)
+	}
+
+	if want := []string{"Intro"}; !reflect.DeepEqual(game.Comments, want) {
+		t.Errorf("got game comments %q, want %q", game.Comments, want)
+	}
+	if want := []string{"Best by test"}; !reflect.DeepEqual(game.Moves[0].Comments, want) {
+		t.Errorf("got comments on 1. e4 %q, want %q", game.Moves[0].Comments, want)
+	}
+	// The comment opening the second variation is attached to the move it branches from.
+	if want := []string{"Or"}; !reflect.DeepEqual(game.Moves[1].Comments, want) {
+		t.Errorf("got comments on 1... e5 %q, want %q", game.Moves[1].Comments, want)
+	}
+	if want := []string{"Sicilian"}; !reflect.DeepEqual(game.Moves[1].Variations[0][0].Comments, want) {
+		t.Errorf("got comments on 1... c5 %q, want %q", game.Moves[1].Variations[0][0].Comments, want)
+	}
+	if game.Moves[2].Comments != nil {
+		t.Errorf("expected no comments on 2. Nf3, got %q", game.Moves[2].Comments)
+	}
+}
+
+func TestParseCommentOptions(t *testing.T) {
+	t.Parallel()
+	pgn := `{Intro} 1. e4 {Good} e5 ; Solid
+2. Nf3 *`
+
+	t.Run("skip comments", func(t *testing.T) {
+		game, err := chessnote.ParseString(pgn, chessnote.WithSkipComments())
+		if err != nil {
+			t.Fatalf("ParseString() failed: %v", err)
+		}
+		if game.Comments != nil {
+			t.Errorf("expected no game comments, got %q", game.Comments)
+		}
+		for i, m := range game.Moves {
+			if m.Comments != nil {
+				t.Errorf("move %d: expected no comments, got %q", i+1, m.Comments)
+			}
+		}
+	})
+
+	t.Run("comment handler", func(t *testing.T) {
+		type seen struct {
+			move    string
+			comment string
+		}
+		var got []seen
+		handler := func(move *chessnote.Move, comment string) {
+			name := "game"
+			if move != nil {
+				name = string(rune('a'+move.To.File)) + string(rune('1'+move.To.Rank))
+			}
+			got = append(got, seen{name, comment})
+		}
+
+		game, err := chessnote.ParseString(pgn, chessnote.WithCommentHandler(handler))
+		if err != nil {
+			t.Fatalf("ParseString() failed: %v", err)
+		}
+		want := []seen{{"game", "Intro"}, {"e4", "Good"}, {"e5", "Solid"}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("handler got %v, want %v", got, want)
+		}
+		if game.Comments != nil || game.Moves[0].Comments != nil || game.Moves[1].Comments != nil {
+			t.Errorf("expected comments passed to the handler not to be retained")
+		}
+	})
+
+	t.Run("last option wins", func(t *testing.T) {
+		called := false
+		handler := func(*chessnote.Move, string) { called = true }
+		_, err := chessnote.ParseString(pgn, chessnote.WithCommentHandler(handler), chessnote.WithSkipComments())
+		if err != nil {
+			t.Fatalf("ParseString() failed: %v", err)
+		}
+		if called {
+			t.Errorf("expected WithSkipComments to override an earlier WithCommentHandler")
+		}
+	})
+
+	t.Run("lazy comments", func(t *testing.T) {
+		games := []string{pgn, "1. d4 {  Queen's pawn } (1. c4 { English}) d5 {a} {b} *"}
+		for _, src := range games {
+			eager, err := chessnote.ParseString(src)
+			if err != nil {
+				t.Fatalf("ParseString() failed: %v", err)
+			}
+			lazy, err := chessnote.ParseBytes([]byte(src), chessnote.WithLazyComments())
+			if err != nil {
+				t.Fatalf("ParseBytes() failed: %v", err)
+			}
+			if !reflect.DeepEqual(lazy.Comments, eager.Comments) {
+				t.Errorf("got game comments %q, want %q", lazy.Comments, eager.Comments)
+			}
+			for i, m := range lazy.Moves {
+				if m.Comments != nil {
+					t.Errorf("move %d: expected comments to stay in the source, got %q", i+1, m.Comments)
+				}
+				if got, want := m.Comment(), eager.Moves[i].Comment(); got != want {
+					t.Errorf("move %d: Comment() = %q, want %q", i+1, got, want)
+				}
+			}
+			if got, want := lazy.ToPGN(), eager.ToPGN(); got != want {
+				t.Errorf("ToPGN() = %q, want %q", got, want)
+			}
+			if diffs := chessnote.DiffGames(lazy, eager); len(diffs) != 0 {
+				t.Errorf("DiffGames() = %q, want no differences", diffs)
+			}
+			if got, want := lazy.CommentsByPly(), eager.CommentsByPly(); !reflect.DeepEqual(got, want) {
+				t.Errorf("CommentsByPly() = %v, want %v", got, want)
+			}
+			var lazyTree, eagerTree strings.Builder
+			if err := lazy.DumpTree(&lazyTree); err != nil {
+				t.Fatalf("DumpTree() failed: %v", err)
+			}
+			if err := eager.DumpTree(&eagerTree); err != nil {
+				t.Fatalf("DumpTree() failed: %v", err)
+			}
+			if got, want := lazyTree.String(), eagerTree.String(); got != want {
+				t.Errorf("DumpTree() = %q, want %q", got, want)
+			}
+			for i, m := range lazy.OpeningLine(len(lazy.Moves)) {
+				if got := m.Comment(); got != "" {
+					t.Errorf("OpeningLine() move %d: Comment() = %q, want \"\"", i+1, got)
+				}
+			}
+		}
+
+		game, err := chessnote.ParseBytes([]byte(games[1]), chessnote.WithLazyComments())
+		if err != nil {
+			t.Fatalf("ParseBytes() failed: %v", err)
+		}
+		if got, want := game.Moves[0].Comment(), "Queen's pawn"; got != want {
+			t.Errorf("Comment() = %q, want %q", got, want)
+		}
+		if got, want := game.Moves[0].Variations[0][0].Comment(), "English"; got != want {
+			t.Errorf("variation Comment() = %q, want %q", got, want)
+		}
+		if got, want := game.Moves[1].Comment(), "a b"; got != want {
+			t.Errorf("Comment() = %q, want %q", got, want)
+		}
+	})
+}
+
+func TestParseMultiLineComments(t *testing.T) {
+	t.Parallel()
+	pgn := "1. e4 {A long note\n  that wraps\tacross lines} e5 *"
+
+	tests := []struct {
+		name string
+		opts []chessnote.ParserOption
+		want string
+	}{
+		{"newlines preserved by default", nil, "A long note\n  that wraps\tacross lines"},
+		{"whitespace collapsed", []chessnote.ParserOption{chessnote.WithCommentWhitespaceCollapse()}, "A long note that wraps across lines"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game, err := chessnote.ParseString(pgn, tt.opts...)
+			if err != nil {
+				t.Fatalf("ParseString() failed: %v", err)
+			}
+			if want := []string{tt.want}; !reflect.DeepEqual(game.Moves[0].Comments, want) {
+				t.Errorf("got comments %q, want %q", game.Moves[0].Comments, want)
+			}
+		})
+	}
+}
+
+func TestParseLenientTags(t *testing.T) {
+	t.Parallel()
+	pgn := `[Event 'Club Championship']
+[Round 3]
+[White "Player, A"]
+[Site Some Town ]
+
+1. e4 *`
+
+	t.Run("strict tags reject malformed values", func(t *testing.T) {
+		if _, err := chessnote.ParseString(pgn); err == nil {
+			t.Error("expected an error for malformed tag values, but got nil")
+		}
+	})
+
+	t.Run("lenient tags recover malformed values", func(t *testing.T) {
+		game, err := chessnote.ParseString(pgn, chessnote.WithLenientTags())
+		if err != nil {
+			t.Fatalf("ParseString() failed: %v", err)
+		}
+		want := map[string]string{
+			"Event": "Club Championship",
+			"Round": "3",
+			"White": "Player, A",
+			"Site":  "Some Town",
+		}
+		if !reflect.DeepEqual(game.Tags, want) {
+			t.Errorf("got tags %v, want %v", game.Tags, want)
+		}
+		if len(game.Moves) != 1 {
+			t.Errorf("expected 1 move, got %d", len(game.Moves))
+		}
+	})
+}
+
+func TestParseTrimTagValues(t *testing.T) {
+	t.Parallel()
+	pgn := "[Event \" Padded Name \"]\n[Site \"\uFEFFBOM Town\"]\n[White \"\t\uFEFF Player, A\"]\n[Black \"Player, B\"]\n\n1. e4 *"
+
+	t.Run("default is byte-exact", func(t *testing.T) {
+		game, err := chessnote.ParseString(pgn)
+		if err != nil {
+			t.Fatalf("ParseString() failed: %v", err)
+		}
+		want := map[string]string{
+			"Event": " Padded Name ",
+			"Site":  "\uFEFFBOM Town",
+			"White": "\t\uFEFF Player, A",
+			"Black": "Player, B",
+		}
+		if !reflect.DeepEqual(game.Tags, want) {
+			t.Errorf("got tags %q, want %q", game.Tags, want)
+		}
+	})
+
+	t.Run("trimmed values", func(t *testing.T) {
+		game, err := chessnote.ParseString(pgn, chessnote.WithTrimTagValues())
+		if err != nil {
+			t.Fatalf("ParseString() failed: %v", err)
+		}
+		want := map[string]string{
+			"Event": "Padded Name",
+			"Site":  "BOM Town",
+			"White": "Player, A",
+			"Black": "Player, B",
+		}
+		if !reflect.DeepEqual(game.Tags, want) {
+			t.Errorf("got tags %q, want %q", game.Tags, want)
+		}
+	})
+}
+
+func TestParseCaseInsensitivePieces(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		pgn  string
+		want chessnote.Move
+	}{
+		{"lowercase knight", "1. nf3 *", chessnote.Move{Piece: chessnote.Knight, To: chessnote.Square{File: 5, Rank: 2}}},
+		{"lowercase queen capture", "1. qxd5 *", chessnote.Move{Piece: chessnote.Queen, To: chessnote.Square{File: 3, Rank: 4}, IsCapture: true}},
+		{"lowercase king with check", "1. ke2+ *", chessnote.Move{Piece: chessnote.King, To: chessnote.Square{File: 4, Rank: 1}, IsCheck: true}},
+		{"bxc4 stays a pawn capture", "1. bxc4 *", chessnote.Move{Piece: chessnote.Pawn, From: chessnote.Square{File: 1}, HasFromFile: true, To: chessnote.Square{File: 2, Rank: 3}, IsCapture: true}},
+		{"b4 stays a pawn move", "1. b4 *", chessnote.Move{Piece: chessnote.Pawn, To: chessnote.Square{File: 1, Rank: 3}}},
+		{"bc4 is a bishop move", "1. bc4 *", chessnote.Move{Piece: chessnote.Bishop, To: chessnote.Square{File: 2, Rank: 3}}},
+		{"lowercase promotion", "1. e8=q *", chessnote.Move{Piece: chessnote.Pawn, To: chessnote.Square{File: 4, Rank: 7}, Promotion: chessnote.Queen}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game, err := chessnote.ParseString(tt.pgn, chessnote.WithCaseInsensitivePieces())
+			if err != nil {
+				t.Fatalf("ParseString() failed: %v", err)
+			}
+			if len(game.Moves) != 1 {
+				t.Fatalf("expected 1 move, got %d", len(game.Moves))
+			}
+			if !reflect.DeepEqual(game.Moves[0], tt.want) {
+				t.Errorf("got move %+v, want %+v", game.Moves[0], tt.want)
+			}
+		})
+	}
+
+	for _, pgn := range []string{"1. nf3 *", "1. bc4 *", "1. e8=q *"} {
+		if _, err := chessnote.ParseString(pgn); err == nil {
+			t.Errorf("ParseString(%q) without the option expected an error, but got nil", pgn)
+		}
+	}
+}
+
+func TestParseTokenErrorHandler(t *testing.T) {
+	t.Parallel()
+	pgn := "] 1. e4 @ e5 2. Nf3 & Nc6 *"
+
+	var seen []chessnote.Token
+	game, err := chessnote.ParseString(pgn, chessnote.WithTokenErrorHandler(func(tok chessnote.Token) bool {
+		seen = append(seen, tok)
+		return true
+	}))
+	if err != nil {
+		t.Fatalf("ParseString() failed: %v", err)
+	}
+	if len(game.Moves) != 4 || game.Result != "*" {
+		t.Errorf("got %d moves and result %q, want 4 moves and %q", len(game.Moves), game.Result, "*")
+	}
+	wantSeen := []chessnote.Token{
+		{Kind: chessnote.TokenRBracket, Literal: "]", Line: 1, Column: 1},
+		{Kind: chessnote.TokenIllegal, Literal: "@", Line: 1, Column: 9},
+		{Kind: chessnote.TokenIllegal, Literal: "&", Line: 1, Column: 21},
+	}
+	if !reflect.DeepEqual(seen, wantSeen) {
+		t.Errorf("handler got %+v, want %+v", seen, wantSeen)
+	}
+	wantWarnings := []string{
+		`line 1, column 1: skipped unexpected token "]"`,
+		`line 1, column 9: skipped unexpected token "@"`,
+		`line 1, column 21: skipped unexpected token "&"`,
+	}
+	if !reflect.DeepEqual(game.ParseWarnings, wantWarnings) {
+		t.Errorf("got warnings %q, want %q", game.ParseWarnings, wantWarnings)
+	}
+
+	t.Run("handler aborts", func(t *testing.T) {
+		calls := 0
+		_, err := chessnote.ParseString("1. e4 @ e5 & *", chessnote.WithTokenErrorHandler(func(tok chessnote.Token) bool {
+			calls++
+			return tok.Literal != "&"
+		}))
+		if err == nil {
+			t.Fatal("expected an error when the handler returns false, but got nil")
+		}
+		if calls != 2 {
+			t.Errorf("handler called %d times, want 2", calls)
+		}
+	})
+
+	t.Run("no handler", func(t *testing.T) {
+		if _, err := chessnote.ParseString("1. e4 @ e5 *"); err == nil {
+			t.Error("expected an error without a handler, but got nil")
+		}
+	})
+}
+
+func TestParserConfig(t *testing.T) {
+	t.Parallel()
+	newParser := func(opts ...chessnote.ParserOption) *chessnote.Parser {
+		return chessnote.NewParser(strings.NewReader("1. e4"), opts...)
+	}
+
+	t.Run("default config", func(t *testing.T) {
+		got := newParser().Config()
+		if !reflect.DeepEqual(got, chessnote.DefaultParserConfig()) {
+			t.Errorf("got config %+v, want the default config", got)
+		}
+		if !got.Strict {
+			t.Error("expected the default config to be strict")
+		}
+	})
+
+	t.Run("functional options are reflected", func(t *testing.T) {
+		got := newParser(chessnote.WithLaxParsing(), chessnote.WithLenientTags()).Config()
+		if got.Strict || !got.LenientTags {
+			t.Errorf("got config %+v, want lax parsing with lenient tags", got)
+		}
+	})
+
+	t.Run("WithConfig replaces the config", func(t *testing.T) {
+		cfg := chessnote.ParserConfig{SkipComments: true, CollapseCommentWhitespace: true}
+		got := newParser(chessnote.WithLenientTags(), chessnote.WithConfig(cfg)).Config()
+		if !reflect.DeepEqual(got, cfg) {
+			t.Errorf("got config %+v, want %+v", got, cfg)
+		}
+	})
+
+	t.Run("later options apply on top of WithConfig", func(t *testing.T) {
+		got := newParser(chessnote.WithConfig(chessnote.DefaultParserConfig()), chessnote.WithLaxParsing()).Config()
+		if got.Strict {
+			t.Error("expected WithLaxParsing after WithConfig to disable strict mode")
+		}
+	})
+
+	t.Run("bulk config drives parsing", func(t *testing.T) {
+		cfg := chessnote.DefaultParserConfig()
+		cfg.Strict = false
+		game, err := chessnote.ParseString("1. e4 {note} e5", chessnote.WithConfig(cfg))
+		if err != nil {
+			t.Fatalf("ParseString() failed: %v", err)
+		}
+		if len(game.Moves) != 2 {
+			t.Errorf("expected 2 moves, got %d", len(game.Moves))
+		}
+	})
+}
+
+func TestParsePooledMoveBuffers(t *testing.T) {
+	t.Parallel()
+	data, err := os.ReadFile("../examples/multiple-game-pgn/Kasparov.pgn")
+	if err != nil {
+		t.Fatalf("failed to read PGN file: %v", err)
+	}
+	games := chessnote.SplitMultiGame(string(data))
+
+	var pooled []*chessnote.Game
+	for i, pgn := range games {
+		want, err := chessnote.ParseString(pgn)
+		if err != nil {
+			t.Fatalf("game %d: ParseString() failed: %v", i+1, err)
+		}
+		got, err := chessnote.ParseString(pgn, chessnote.WithPooledMoveBuffers())
+		if err != nil {
+			t.Fatalf("game %d: ParseString() with pooled buffers failed: %v", i+1, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("game %d: pooled parse differs from the default parse", i+1)
+		}
+		if len(got.Moves) != cap(got.Moves) {
+			t.Errorf("game %d: got %d moves with capacity %d, want an exactly-sized slice", i+1, len(got.Moves), cap(got.Moves))
+		}
+		pooled = append(pooled, got)
+	}
+
+	// Games parsed earlier must not be overwritten by buffers reused later.
+	for i, pgn := range games {
+		want, err := chessnote.ParseString(pgn)
+		if err != nil {
+			t.Fatalf("game %d: ParseString() failed: %v", i+1, err)
+		}
+		if !reflect.DeepEqual(pooled[i], want) {
+			t.Errorf("game %d: changed after later games were parsed", i+1)
+		}
+	}
+
+	game, err := chessnote.ParseString("1. e4 (1. d4 d5 (1... Nf6)) e5 *", chessnote.WithPooledMoveBuffers())
+	if err != nil {
+		t.Fatalf("ParseString() failed: %v", err)
+	}
+	variation := game.Moves[0].Variations[0]
+	if len(variation) != 2 || len(variation[1].Variations) != 1 || len(variation[1].Variations[0]) != 1 {
+		t.Errorf("nested variations were not preserved: %+v", game.Moves[0].Variations)
+	}
+}
+
+func TestParseEscapeLines(t *testing.T) {
+	t.Parallel()
+	pgn := "% Exported by a database tool\n[Event \"Test\"]\n\n1. e4 e5\n% out-of-band data {not a comment}\n2. Nf3 *"
+	game, err := chessnote.ParseString(pgn)
+	if err != nil {
+		t.Fatalf("ParseString() failed: %v", err)
+	}
+	if len(game.Moves) != 3 {
+		t.Errorf("got %d moves, want 3", len(game.Moves))
+	}
+	if game.Moves[1].Comments != nil {
+		t.Errorf("expected the escape line to be ignored, got comments %q", game.Moves[1].Comments)
+	}
+}
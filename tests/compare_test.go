@@ -0,0 +1,115 @@
+package chessnote_test
+
+import (
+	"testing"
+
+	"github.com/YashBhalodi/chessnote"
+)
+
+func TestMoveEqual(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"e4", "e4", true},
+		{"Nf3", "Nf3+", true},
+		{"Nd2", "Nbd2", true},
+		{"Nbd2", "Nfd2", false},
+		{"N1c3", "N5c3", false},
+		{"Bxe5", "Be5", true},
+		{"Nf3", "Nc3", false},
+		{"Nf3", "Bf3", false},
+		{"e8=Q", "e8=N", false},
+		{"O-O", "O-O+", true},
+		{"O-O", "O-O-O", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+" "+tt.b, func(t *testing.T) {
+			a, b := parseMove(t, tt.a), parseMove(t, tt.b)
+			if got := a.Equal(b); got != tt.want {
+				t.Errorf("%s.Equal(%s) = %t, want %t", tt.a, tt.b, got, tt.want)
+			}
+			if got := b.Equal(a); got != tt.want {
+				t.Errorf("%s.Equal(%s) = %t, want %t", tt.b, tt.a, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFirstDeviation(t *testing.T) {
+	t.Parallel()
+	reference, err := chessnote.ParseString("1. e4 c5 2. Nf3 d6 3. d4 cxd4 4. Nxd4 Nf6 5. Nc3 a6 *")
+	if err != nil {
+		t.Fatalf("ParseString() failed: %v", err)
+	}
+	tests := []struct {
+		name string
+		pgn  string
+		want int
+	}{
+		{"deviates on Black's second move", "1. e4 c5 2. Nf3 Nc6 3. d4 *", 4},
+		{"deviates on the first move", "1. d4 d5 *", 1},
+		{"follows the whole reference", "1. e4 c5 2. Nf3 d6 3. d4 cxd4 4. Nxd4 Nf6 5. Nc3 a6 6. Be3 e5 *", -1},
+		{"ends while following the reference", "1. e4 c5 2. Nf3 1/2-1/2", -1},
+		{"annotations do not matter", "1. e4! c5 2. Nf3 d6 3. d4 cxd4 4. Nxd4 Nf6 5. Nc3 g6 *", 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game, err := chessnote.ParseString(tt.pgn)
+			if err != nil {
+				t.Fatalf("ParseString() failed: %v", err)
+			}
+			if got := chessnote.FirstDeviation(game, reference.Moves); got != tt.want {
+				t.Errorf("FirstDeviation() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGameEqualMoves(t *testing.T) {
+	t.Parallel()
+	base := "[Event \"Club\"]\n[Result \"1-0\"]\n\n1. e4 e5 2. Nf3 Nc6 3. Bb5 a6 1-0"
+	tests := []struct {
+		name  string
+		other string
+		want  bool
+	}{
+		{"identical", base, true},
+		{"different result", "[Event \"Club\"]\n[Result \"1/2-1/2\"]\n\n1. e4 e5 2. Nf3 Nc6 3. Bb5 a6 1/2-1/2", true},
+		{"no result", "1. e4 e5 2. Nf3 Nc6 3. Bb5 a6", true},
+		{"different tags and annotations", "[Event \"Online\"]\n\n1. e4! {Best} e5 2. Nf3 (2. f4) Nc6 3. Bb5 $1 a6 *", true},
+		{"redundant disambiguation", "1. e2-e4 e5 2. Ngf3 Nbc6 3. Bfb5 a6 *", true},
+		{"different move", "1. e4 e5 2. Nf3 Nc6 3. Bc4 a6 1-0", false},
+		{"shorter", "1. e4 e5 2. Nf3 Nc6 3. Bb5 1-0", false},
+		{"different start", "[SetUp \"1\"]\n[FEN \"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w - - 0 1\"]\n\n1. e4 e5 2. Nf3 Nc6 3. Bb5 a6 1-0", false},
+	}
+	game, err := chessnote.ParseString(base)
+	if err != nil {
+		t.Fatalf("ParseString() failed: %v", err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			other, err := chessnote.ParseString(tt.other, chessnote.WithLaxParsing())
+			if err != nil {
+				t.Fatalf("ParseString() failed: %v", err)
+			}
+			if got := game.EqualMoves(other); got != tt.want {
+				t.Errorf("EqualMoves() = %t, want %t", got, tt.want)
+			}
+			if got := other.EqualMoves(game); got != tt.want {
+				t.Errorf("EqualMoves() in reverse = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+// parseMove parses a single SAN move.
+func parseMove(t *testing.T, san string) chessnote.Move {
+	t.Helper()
+	game, err := chessnote.ParseString("1. " + san + " *")
+	if err != nil {
+		t.Fatalf("ParseString(%q) failed: %v", san, err)
+	}
+	return game.Moves[0]
+}
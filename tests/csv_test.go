@@ -0,0 +1,57 @@
+package chessnote_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/YashBhalodi/chessnote"
+)
+
+func TestGameWriteCSV(t *testing.T) {
+	t.Parallel()
+	game, err := chessnote.ParseString(`1. e4 {Best by test} e5 2. Nf3 $1 $14 Nc6 3. Bb5 a6 4. Bxc6 dxc6 5. O-O f6 *`)
+	if err != nil {
+		t.Fatalf("ParseString() failed: %v", err)
+	}
+	var sb strings.Builder
+	if err := game.WriteCSV(&sb); err != nil {
+		t.Fatalf("WriteCSV() failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(sb.String(), "\n"), "\n")
+	want := map[int]string{
+		0: "ply,side,san,from,to,piece,capture,check,mate,nags,comment",
+		1: "1,white,e4,e2,e4,pawn,false,false,false,,Best by test",
+		3: "3,white,Nf3,g1,f3,knight,false,false,false,$1 $14,",
+		8: "8,black,dxc6,d7,c6,pawn,true,false,false,,",
+		9: "9,white,O-O,e1,g1,king,false,false,false,,",
+	}
+	if len(lines) != len(game.Moves)+1 {
+		t.Fatalf("got %d lines, want %d", len(lines), len(game.Moves)+1)
+	}
+	for i, w := range want {
+		if lines[i] != w {
+			t.Errorf("line %d: got %q, want %q", i, lines[i], w)
+		}
+	}
+}
+
+func TestGameWriteCSVIllegalMove(t *testing.T) {
+	t.Parallel()
+	game, err := chessnote.ParseString(`[FEN "4k3/8/8/8/8/8/8/4K3 b - - 0 1"]
+1... Kd7 2. Kf5 Ke6 *`)
+	if err != nil {
+		t.Fatalf("ParseString() failed: %v", err)
+	}
+	var sb strings.Builder
+	if err := game.WriteCSV(&sb); err != nil {
+		t.Fatalf("WriteCSV() failed: %v", err)
+	}
+	want := `ply,side,san,from,to,piece,capture,check,mate,nags,comment
+1,black,Kd7,e8,d7,king,false,false,false,,
+2,white,Kf5,,f5,king,false,false,false,,
+3,black,Ke6,,e6,king,false,false,false,,
+`
+	if sb.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", sb.String(), want)
+	}
+}
@@ -0,0 +1,120 @@
+package chessnote_test
+
+import (
+	"errors"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/YashBhalodi/chessnote"
+)
+
+func TestGameCursor(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		pgn  string
+	}{
+		{"castling on both sides", "1. e4 e5 2. Nf3 Nc6 3. Bc4 Bc5 4. O-O d6 5. d3 Be6 6. Nc3 Qd7 7. Be3 O-O-O *"},
+		{"en passant and promotion", "1. e4 d5 2. e5 f5 3. exf6 g6 4. fxe7 Nf6 5. exd8=Q+ Kxd8 *"},
+		{"chess960 castling", "[Variant \"Chess960\"]\n[FEN \"bqnbrkrn/pppppppp/8/8/8/8/PPPPPPPP/BQNBRKRN w KQkq - 0 1\"]\n\n1. O-O O-O 2. Nd3 Nd6 *"},
+		{"black to move first", "[FEN \"4k3/8/8/8/4p3/8/3P4/4K3 b - - 3 20\"]\n\n20... Kd7 21. d4 exd3 *"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game, err := chessnote.ParseString(tt.pgn)
+			if err != nil {
+				t.Fatalf("ParseString() failed: %v", err)
+			}
+			checkCursor(t, game)
+		})
+	}
+
+	t.Run("fixture", func(t *testing.T) {
+		data, err := os.ReadFile("../examples/multiple-game-pgn/Kasparov.pgn")
+		if err != nil {
+			t.Fatalf("failed to read PGN file: %v", err)
+		}
+		for _, pgn := range chessnote.SplitMultiGame(string(data))[:10] {
+			game, err := chessnote.ParseString(pgn)
+			if err != nil {
+				t.Fatalf("ParseString() failed: %v", err)
+			}
+			checkCursor(t, game)
+		}
+	})
+}
+
+// checkCursor steps a cursor through the whole mainline of game and back to
+// the start, comparing every position with the one Positions reports.
+func checkCursor(t *testing.T, game *chessnote.Game) {
+	t.Helper()
+	positions, err := game.Positions()
+	if err != nil {
+		t.Fatalf("Positions() failed: %v", err)
+	}
+	c, err := chessnote.NewGameCursor(game)
+	if err != nil {
+		t.Fatalf("NewGameCursor() failed: %v", err)
+	}
+	if c.Back() {
+		t.Errorf("Back() at the start = true, want false")
+	}
+	for ply := 1; ply <= len(game.Moves); ply++ {
+		if err := c.Forward(); err != nil {
+			t.Fatalf("Forward() to ply %d failed: %v", ply, err)
+		}
+		if got, want := c.Board().FEN(), positions[ply].FEN(); got != want {
+			t.Fatalf("ply %d forward: got %s, want %s", ply, got, want)
+		}
+	}
+	if err := c.Forward(); err != io.EOF {
+		t.Errorf("Forward() at the end = %v, want io.EOF", err)
+	}
+	for ply := len(game.Moves) - 1; ply >= 0; ply-- {
+		if !c.Back() {
+			t.Fatalf("Back() to ply %d = false, want true", ply)
+		}
+		if c.Ply() != ply {
+			t.Errorf("Ply() = %d, want %d", c.Ply(), ply)
+		}
+		if got, want := c.Board().FEN(), positions[ply].FEN(); got != want {
+			t.Fatalf("ply %d back: got %s, want %s", ply, got, want)
+		}
+	}
+	if c.Back() {
+		t.Errorf("Back() at the start = true, want false")
+	}
+}
+
+func TestGameCursorIllegalMove(t *testing.T) {
+	t.Parallel()
+	game, err := chessnote.ParseString("1. e4 e5 2. Ke3 *")
+	if err != nil {
+		t.Fatalf("ParseString() failed: %v", err)
+	}
+	c, err := chessnote.NewGameCursor(game)
+	if err != nil {
+		t.Fatalf("NewGameCursor() failed: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := c.Forward(); err != nil {
+			t.Fatalf("Forward() failed: %v", err)
+		}
+	}
+	before := c.Board().FEN()
+	var replayErr *chessnote.ReplayError
+	if err := c.Forward(); !errors.As(err, &replayErr) || replayErr.Ply != 3 {
+		t.Fatalf("Forward() error = %v, want a *ReplayError for ply 3", err)
+	}
+	if c.Ply() != 2 || c.Board().FEN() != before {
+		t.Errorf("expected the position to be unchanged after an illegal move, got ply %d: %s", c.Ply(), c.Board().FEN())
+	}
+	if !c.Back() || c.Board().FEN() != "rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1" {
+		t.Errorf("Back() after an illegal move reached %s", c.Board().FEN())
+	}
+
+	if _, err := chessnote.NewGameCursor(&chessnote.Game{Tags: map[string]string{"FEN": "bad"}}); err == nil {
+		t.Error("NewGameCursor() with an invalid FEN tag: expected an error, got nil")
+	}
+}
@@ -0,0 +1,53 @@
+package chessnote_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/YashBhalodi/chessnote"
+)
+
+func TestGameDumpTree(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		pgn  string
+		want string
+	}{
+		{
+			name: "mainline",
+			pgn:  "1. e4 e5 2. Nf3 *",
+			want: "1. e4\n1... e5\n2. Nf3\n",
+		},
+		{
+			name: "nested variations with annotations",
+			pgn:  "1. e4! {Best} e5 (1... c5 2. Nf3 (2. c3 $6)) 2. Nf3 *",
+			want: "1. e4 $1 {Best}\n" +
+				"1... e5\n" +
+				"  1... c5\n" +
+				"  2. Nf3\n" +
+				"    2. c3 $6\n" +
+				"2. Nf3\n",
+		},
+		{
+			name: "black to move from FEN",
+			pgn:  "[FEN \"4k3/8/8/8/8/8/4P3/4K3 b - - 0 30\"]\n\n30... Kd7 (30... Kf7) 31. e4 *",
+			want: "30... Kd7\n  30... Kf7\n31. e4\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game, err := chessnote.ParseString(tt.pgn)
+			if err != nil {
+				t.Fatalf("ParseString() failed: %v", err)
+			}
+			var sb strings.Builder
+			if err := game.DumpTree(&sb); err != nil {
+				t.Fatalf("DumpTree() failed: %v", err)
+			}
+			if got := sb.String(); got != tt.want {
+				t.Errorf("DumpTree() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package chessnote_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/YashBhalodi/chessnote"
+)
+
+func TestGameToPGN(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		pgn  string
+		want string
+	}{
+		{
+			name: "tags in export order",
+			pgn:  `[Result "1-0"] [White "A"] [Event "E"] 1. e4 e5 2. Nf3 1-0`,
+			want: "[Event \"E\"]\n[White \"A\"]\n[Result \"1-0\"]\n\n1. e4 e5 2. Nf3 1-0\n",
+		},
+		{
+			name: "annotations and variations",
+			pgn:  `{Intro} 1. e4! {Best} e5 (1... c5 $2 {Sicilian}) 2. Nf3 Nc6 *`,
+			want: "{Intro} 1. e4 $1 {Best} 1... e5 (1... c5 $2 {Sicilian}) 2. Nf3 Nc6 *\n",
+		},
+		{
+			name: "black to move from FEN",
+			pgn:  "[FEN \"4k3/8/8/8/8/8/4P3/4K3 b - - 0 30\"]\n\n30... Kd7 31. e4 *",
+			want: "[FEN \"4k3/8/8/8/8/8/4P3/4K3 b - - 0 30\"]\n\n30... Kd7 31. e4 *\n",
+		},
+		{
+			name: "comments after the result",
+			pgn:  `1. d4 1/2-1/2 {Agreed}`,
+			want: "1. d4 1/2-1/2 {Agreed}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game, err := chessnote.ParseString(tt.pgn)
+			if err != nil {
+				t.Fatalf("ParseString() failed: %v", err)
+			}
+			if got := game.ToPGN(); got != tt.want {
+				t.Errorf("ToPGN() =\n%s\nwant:\n%s", got, tt.want)
+			}
+			var sb strings.Builder
+			if err := game.WritePGN(&sb); err != nil {
+				t.Fatalf("WritePGN() error = %v", err)
+			}
+			if sb.String() != tt.want {
+				t.Errorf("WritePGN() wrote\n%s\nwant:\n%s", sb.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestGameToPGNWrapsLines(t *testing.T) {
+	t.Parallel()
+	game, err := chessnote.ParseString(operaGame)
+	if err != nil {
+		t.Fatalf("ParseString() failed: %v", err)
+	}
+	for i, line := range strings.Split(game.ToPGN(), "\n") {
+		if len(line) > 80 {
+			t.Errorf("line %d is %d characters long: %q", i+1, len(line), line)
+		}
+	}
+}
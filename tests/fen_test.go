@@ -0,0 +1,261 @@
+package chessnote_test
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/YashBhalodi/chessnote"
+)
+
+func TestParseFEN(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		fen     string
+		wantErr bool
+	}{
+		{"starting position", chessnote.StartingFEN, false},
+		{"after 1. e4", "rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1", false},
+		{"no castling rights", "4k3/8/8/8/8/8/8/4K3 w - - 12 40", false},
+		{"too few ranks", "8/8/8/8/8/8/8 w - - 0 1", true},
+		{"rank too long", "9/8/8/8/8/8/8/8 w - - 0 1", true},
+		{"invalid piece", "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNX w KQkq - 0 1", true},
+		{"invalid side to move", "8/8/8/8/8/8/8/8 x - - 0 1", true},
+		{"invalid castling", "8/8/8/8/8/8/8/8 w X - 0 1", true},
+		{"invalid en passant square", "8/8/8/8/8/8/8/8 w - e9 0 1", true},
+		{"invalid fullmove number", "8/8/8/8/8/8/8/8 w - - 0 0", true},
+		{"missing fields", "8/8/8/8/8/8/8/8 w", true},
+		{"missing fullmove number", "8/8/8/8/8/8/8/8 w - - 0", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := chessnote.ParseFEN(tt.fen)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseFEN() expected an error, but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseFEN() error = %v", err)
+			}
+			if got := b.FEN(); got != tt.fen {
+				t.Errorf("FEN() = %q, want %q", got, tt.fen)
+			}
+		})
+	}
+}
+
+func TestParseFENFileCastlingRights(t *testing.T) {
+	t.Parallel()
+	const placement = "bqnbrkrn/pppppppp/8/8/8/8/PPPPPPPP/BQNBRKRN"
+	tests := []struct {
+		name     string
+		castling string
+		want     string
+		wantErr  bool
+	}{
+		{"shredder", "GEge", "KQkq", false},
+		{"one side each", "Ge", "Kq", false},
+		{"mixed with KQkq", "KEkq", "KQkq", false},
+		{"no rook on the file", "Dd", "", true},
+		{"king's file", "Ff", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := chessnote.ParseFEN(placement + " w " + tt.castling + " - 0 1")
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseFEN() expected an error, but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseFEN() error = %v", err)
+			}
+			if got, want := b.FEN(), placement+" w "+tt.want+" - 0 1"; got != want {
+				t.Errorf("FEN() = %q, want %q", got, want)
+			}
+		})
+	}
+
+	game, err := chessnote.ParseString("[Variant \"Chess960\"]\n[SetUp \"1\"]\n[FEN \"" + placement + " w GEge - 0 1\"]\n\n1. O-O O-O 2. Nd3 Nd6 *")
+	if err != nil {
+		t.Fatalf("ParseString() failed: %v", err)
+	}
+	if errs := game.Validate(); errs != nil {
+		t.Errorf("Validate() = %v, want no errors", errs)
+	}
+}
+
+func TestBoardFEN(t *testing.T) {
+	t.Parallel()
+	if got := chessnote.NewBoard().FEN(); got != chessnote.StartingFEN {
+		t.Errorf("NewBoard().FEN() = %q, want %q", got, chessnote.StartingFEN)
+	}
+
+	game, err := chessnote.ParseString("1. e4 c5 2. Nf3 *")
+	if err != nil {
+		t.Fatalf("ParseString() failed: %v", err)
+	}
+	positions, err := game.Positions()
+	if err != nil {
+		t.Fatalf("Positions() failed: %v", err)
+	}
+	want := []string{
+		chessnote.StartingFEN,
+		"rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1",
+		"rnbqkbnr/pp1ppppp/8/2p5/4P3/8/PPPP1PPP/RNBQKBNR w KQkq c6 0 2",
+		"rnbqkbnr/pp1ppppp/8/2p5/4P3/5N2/PPPP1PPP/RNBQKB1R b KQkq - 1 2",
+	}
+	for i, fen := range want {
+		if got := positions[i].FEN(); got != fen {
+			t.Errorf("position %d: FEN() = %q, want %q", i, got, fen)
+		}
+	}
+}
+
+func TestGameReplayFromFENTag(t *testing.T) {
+	t.Parallel()
+	pgn := `[SetUp "1"]
+[FEN "4k3/8/8/8/8/8/4P3/4K3 w - - 0 1"]
+
+1. e4 Kd7 *`
+	game, err := chessnote.ParseString(pgn)
+	if err != nil {
+		t.Fatalf("ParseString() failed: %v", err)
+	}
+	positions, err := game.Positions()
+	if err != nil {
+		t.Fatalf("Positions() failed: %v", err)
+	}
+	want := "8/3k4/8/8/4P3/8/8/4K3 w - - 1 2"
+	if got := positions[len(positions)-1].FEN(); got != want {
+		t.Errorf("final FEN() = %q, want %q", got, want)
+	}
+}
+
+func TestParseFENWithoutMoveCounters(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		short, full string
+	}{
+		{"rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e3", "rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1"},
+		{"4k3/8/8/8/8/8/8/4K2R w K -", "4k3/8/8/8/8/8/8/4K2R w K - 0 1"},
+	}
+	for _, tt := range tests {
+		short, err := chessnote.ParseFEN(tt.short)
+		if err != nil {
+			t.Fatalf("ParseFEN(%q) failed: %v", tt.short, err)
+		}
+		full, err := chessnote.ParseFEN(tt.full)
+		if err != nil {
+			t.Fatalf("ParseFEN(%q) failed: %v", tt.full, err)
+		}
+		if !reflect.DeepEqual(short, full) {
+			t.Errorf("ParseFEN(%q) = %s, want the same board as %s", tt.short, short.FEN(), tt.full)
+		}
+		if short.HalfmoveClock() != 0 || short.FullmoveNumber() != 1 {
+			t.Errorf("ParseFEN(%q): got counters %d and %d, want 0 and 1", tt.short, short.HalfmoveClock(), short.FullmoveNumber())
+		}
+	}
+
+	game, err := chessnote.ParseString("[SetUp \"1\"]\n[FEN \"4k3/8/8/8/8/8/8/4K2R b K -\"]\n\n1... Kd7 2. O-O *")
+	if err != nil {
+		t.Fatalf("ParseString() failed: %v", err)
+	}
+	if len(game.ParseWarnings) != 0 {
+		t.Errorf("expected move numbers to count from move 1, got warnings %q", game.ParseWarnings)
+	}
+	positions, err := game.Positions()
+	if err != nil {
+		t.Fatalf("Positions() failed: %v", err)
+	}
+	if got, want := positions[2].FEN(), "8/3k4/8/8/8/8/8/5RK1 b - - 2 2"; got != want {
+		t.Errorf("got final position %s, want %s", got, want)
+	}
+}
+
+func TestGameReplaySeedsMoveCountersFromFENTag(t *testing.T) {
+	t.Parallel()
+	pgn := `[SetUp "1"]
+[FEN "4k3/8/8/8/8/8/8/4K2R w - - 40 55"]
+
+55. Rh7 Kd8 56. Kd2 *`
+	game, err := chessnote.ParseString(pgn)
+	if err != nil {
+		t.Fatalf("ParseString() failed: %v", err)
+	}
+	positions, err := game.Positions()
+	if err != nil {
+		t.Fatalf("Positions() failed: %v", err)
+	}
+	for i, want := range []struct{ halfmove, fullmove int }{{40, 55}, {41, 55}, {42, 56}, {43, 56}} {
+		if got := positions[i].HalfmoveClock(); got != want.halfmove {
+			t.Errorf("position %d: HalfmoveClock() = %d, want %d", i, got, want.halfmove)
+		}
+		if got := positions[i].FullmoveNumber(); got != want.fullmove {
+			t.Errorf("position %d: FullmoveNumber() = %d, want %d", i, got, want.fullmove)
+		}
+	}
+	if positions[len(positions)-1].IsFiftyMoveDraw() {
+		t.Errorf("IsFiftyMoveDraw() = true after 43 quiet plies, want false")
+	}
+}
+
+func TestBoardIsFiftyMoveDraw(t *testing.T) {
+	t.Parallel()
+	b, err := chessnote.ParseFEN("4k3/8/8/8/8/8/8/4K2R w - - 98 120")
+	if err != nil {
+		t.Fatalf("ParseFEN() failed: %v", err)
+	}
+	moves := []chessnote.Move{
+		{Piece: chessnote.Rook, To: chessnote.Square{File: 7, Rank: 6}},
+		{Piece: chessnote.King, To: chessnote.Square{File: 3, Rank: 7}},
+	}
+	for i, m := range moves {
+		if b.IsFiftyMoveDraw() {
+			t.Fatalf("IsFiftyMoveDraw() = true after %d plies, want false", i)
+		}
+		if err := b.Apply(m); err != nil {
+			t.Fatalf("Apply() failed: %v", err)
+		}
+	}
+	if !b.IsFiftyMoveDraw() {
+		t.Errorf("IsFiftyMoveDraw() = false with halfmove clock %d, want true", b.HalfmoveClock())
+	}
+}
+
+func TestBoardWriteFEN(t *testing.T) {
+	// Not parallel: AllocsPerRun counts allocations made by any goroutine.
+	game, err := chessnote.ParseString(operaGame)
+	if err != nil {
+		t.Fatalf("ParseString() failed: %v", err)
+	}
+	positions, err := game.Positions()
+	if err != nil {
+		t.Fatalf("Positions() failed: %v", err)
+	}
+	var sb strings.Builder
+	for i, b := range positions {
+		sb.Reset()
+		if err := b.WriteFEN(&sb); err != nil {
+			t.Fatalf("position %d: WriteFEN() error = %v", i, err)
+		}
+		if got, want := sb.String(), b.FEN(); got != want {
+			t.Errorf("position %d: WriteFEN() wrote %q, want %q", i, got, want)
+		}
+	}
+
+	b := positions[len(positions)-1]
+	allocs := testing.AllocsPerRun(100, func() {
+		if err := b.WriteFEN(io.Discard); err != nil {
+			t.Fatalf("WriteFEN() error = %v", err)
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("WriteFEN() made %v allocations per call, want 0", allocs)
+	}
+}
@@ -0,0 +1,327 @@
+package chessnote_test
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/YashBhalodi/chessnote"
+)
+
+func TestGameSubgame(t *testing.T) {
+	t.Parallel()
+	game, err := chessnote.ParseString(`[White "Morphy"]
+1. e4 e5 2. Nf3 d6 (2... Nc6 3. Bb5) 3. d4 Bg4 1-0`)
+	if err != nil {
+		t.Fatalf("ParseString() failed: %v", err)
+	}
+
+	t.Run("middle of the game", func(t *testing.T) {
+		sub, err := game.Subgame(3, 4)
+		if err != nil {
+			t.Fatalf("Subgame() error = %v", err)
+		}
+		if len(sub.Moves) != 2 {
+			t.Fatalf("expected 2 moves, got %d", len(sub.Moves))
+		}
+		if sub.Result != "*" || sub.Tags["Result"] != "*" {
+			t.Errorf("got result %q (tag %q), want %q", sub.Result, sub.Tags["Result"], "*")
+		}
+		wantFEN := "rnbqkbnr/pppp1ppp/8/4p3/4P3/8/PPPP1PPP/RNBQKBNR w KQkq e6 0 2"
+		if sub.Tags["FEN"] != wantFEN {
+			t.Errorf("got FEN tag %q, want %q", sub.Tags["FEN"], wantFEN)
+		}
+		if sub.Tags["SetUp"] != "1" {
+			t.Errorf("got SetUp tag %q, want %q", sub.Tags["SetUp"], "1")
+		}
+		if sub.Tags["White"] != "Morphy" {
+			t.Errorf("expected other tags to be copied, got White = %q", sub.Tags["White"])
+		}
+		if len(sub.Moves[1].Variations) != 1 {
+			t.Errorf("expected the variation on 2... d6 to be preserved")
+		}
+		if _, err := sub.Positions(); err != nil {
+			t.Errorf("expected the subgame to replay from its FEN, got %v", err)
+		}
+	})
+
+	t.Run("PlyCount tag is updated", func(t *testing.T) {
+		counted, err := chessnote.ParseString("[PlyCount \"4\"]\n1. e4 e5 2. Nf3 d6 *")
+		if err != nil {
+			t.Fatalf("ParseString() failed: %v", err)
+		}
+		sub, err := counted.Subgame(2, 3)
+		if err != nil {
+			t.Fatalf("Subgame() error = %v", err)
+		}
+		if sub.Tags["PlyCount"] != "2" {
+			t.Errorf("got PlyCount tag %q, want %q", sub.Tags["PlyCount"], "2")
+		}
+	})
+
+	t.Run("range reaching the end keeps the result", func(t *testing.T) {
+		sub, err := game.Subgame(5, 6)
+		if err != nil {
+			t.Fatalf("Subgame() error = %v", err)
+		}
+		if sub.Result != "1-0" {
+			t.Errorf("got result %q, want %q", sub.Result, "1-0")
+		}
+	})
+
+	t.Run("subgame does not share moves with the original", func(t *testing.T) {
+		sub, err := game.Subgame(1, 4)
+		if err != nil {
+			t.Fatalf("Subgame() error = %v", err)
+		}
+		sub.Moves[3].Variations[0][0].IsCheck = true
+		if game.Moves[3].Variations[0][0].IsCheck {
+			t.Errorf("modifying the subgame changed the original game")
+		}
+	})
+
+	t.Run("invalid ranges", func(t *testing.T) {
+		for _, r := range [][2]int{{0, 2}, {3, 2}, {1, 7}} {
+			if _, err := game.Subgame(r[0], r[1]); err == nil {
+				t.Errorf("Subgame(%d, %d) expected an error, but got nil", r[0], r[1])
+			}
+		}
+	})
+}
+
+func TestGameMainlineMove(t *testing.T) {
+	t.Parallel()
+	game, err := chessnote.ParseString("1. e4 e5 2. Nf3 *")
+	if err != nil {
+		t.Fatalf("ParseString() failed: %v", err)
+	}
+	for ply, want := range map[int]string{1: "e4", 2: "e5", 3: "Nf3"} {
+		if m, ok := game.MainlineMove(ply); !ok || m.String() != want {
+			t.Errorf("MainlineMove(%d) = %v, %t, want %s", ply, m, ok, want)
+		}
+	}
+	for _, ply := range []int{-1, 0, 4} {
+		if m, ok := game.MainlineMove(ply); ok {
+			t.Errorf("MainlineMove(%d) = %v, want no move", ply, m)
+		}
+	}
+	if m, ok := game.LastMove(); !ok || m.String() != "Nf3" {
+		t.Errorf("LastMove() = %v, %t, want Nf3", m, ok)
+	}
+
+	parsed, err := chessnote.ParseString("[Event \"Empty\"]\n", chessnote.WithLaxParsing())
+	if err != nil {
+		t.Fatalf("ParseString() failed: %v", err)
+	}
+	for _, empty := range []*chessnote.Game{parsed, {}} {
+		if m, ok := empty.LastMove(); ok {
+			t.Errorf("LastMove() of an empty game = %v, want no move", m)
+		}
+		if m, ok := empty.MainlineMove(1); ok {
+			t.Errorf("MainlineMove(1) of an empty game = %v, want no move", m)
+		}
+	}
+}
+
+func TestGameSplitAt(t *testing.T) {
+	t.Parallel()
+	game, err := chessnote.ParseString(`[White "Morphy"]
+[PlyCount "6"]
+{Intro} 1. e4 e5 (1... c5) 2. Nf3 d6 (2... Nc6 3. Bb5) 3. d4 Bg4 1-0 {Black resigned}`)
+	if err != nil {
+		t.Fatalf("ParseString() failed: %v", err)
+	}
+
+	tests := []struct {
+		ply                            int
+		wantHead, wantTail             int
+		headVariations, tailVariations int
+	}{
+		{ply: 1, wantHead: 1, wantTail: 5, headVariations: 0, tailVariations: 2},
+		{ply: 2, wantHead: 2, wantTail: 4, headVariations: 1, tailVariations: 1},
+		{ply: 3, wantHead: 3, wantTail: 3, headVariations: 1, tailVariations: 1},
+		{ply: 4, wantHead: 4, wantTail: 2, headVariations: 2, tailVariations: 0},
+		{ply: 5, wantHead: 5, wantTail: 1, headVariations: 2, tailVariations: 0},
+	}
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.ply), func(t *testing.T) {
+			head, tail, err := game.SplitAt(tt.ply)
+			if err != nil {
+				t.Fatalf("SplitAt() error = %v", err)
+			}
+			if len(head.Moves) != tt.wantHead || len(tail.Moves) != tt.wantTail {
+				t.Fatalf("got %d and %d moves, want %d and %d", len(head.Moves), len(tail.Moves), tt.wantHead, tt.wantTail)
+			}
+			if got := countVariations(head.Moves); got != tt.headVariations {
+				t.Errorf("got %d variations in the head, want %d", got, tt.headVariations)
+			}
+			if got := countVariations(tail.Moves); got != tt.tailVariations {
+				t.Errorf("got %d variations in the tail, want %d", got, tt.tailVariations)
+			}
+
+			positions, err := game.Positions()
+			if err != nil {
+				t.Fatalf("Positions() failed: %v", err)
+			}
+			if got, want := tail.Tags["FEN"], positions[tt.ply].FEN(); got != want {
+				t.Errorf("got tail FEN tag %q, want %q", got, want)
+			}
+			if _, err := tail.Positions(); err != nil {
+				t.Errorf("expected the tail to replay from its FEN, got %v", err)
+			}
+			if _, ok := head.Tags["FEN"]; ok {
+				t.Errorf("expected the head to keep the standard starting position, got FEN tag %q", head.Tags["FEN"])
+			}
+			if head.Result != "*" || tail.Result != "1-0" {
+				t.Errorf("got results %q and %q, want %q and %q", head.Result, tail.Result, "*", "1-0")
+			}
+			if head.Tags["PlyCount"] != strconv.Itoa(tt.wantHead) || tail.Tags["PlyCount"] != strconv.Itoa(tt.wantTail) {
+				t.Errorf("got PlyCount tags %q and %q", head.Tags["PlyCount"], tail.Tags["PlyCount"])
+			}
+			if !reflect.DeepEqual(head.Comments, []string{"Intro"}) || !reflect.DeepEqual(tail.ResultComments, []string{"Black resigned"}) {
+				t.Errorf("got head comments %q and tail result comments %q", head.Comments, tail.ResultComments)
+			}
+		})
+	}
+
+	for _, ply := range []int{0, 6, -1} {
+		if _, _, err := game.SplitAt(ply); err == nil {
+			t.Errorf("SplitAt(%d): expected an error, got nil", ply)
+		}
+	}
+}
+
+// countVariations returns the number of variations attached to moves.
+func countVariations(moves []chessnote.Move) int {
+	n := 0
+	for _, m := range moves {
+		n += len(m.Variations)
+	}
+	return n
+}
+
+func TestGameCommentsByPly(t *testing.T) {
+	t.Parallel()
+	game, err := chessnote.ParseString(`{Opening notes} 1. e4 {Best by test} e5 (1... c5 {Sicilian}) 2. Nf3 {Developing} {Attacks e5} Nc6 *`)
+	if err != nil {
+		t.Fatalf("ParseString() failed: %v", err)
+	}
+	want := map[int]string{
+		0: "Opening notes",
+		1: "Best by test",
+		3: "Developing Attacks e5",
+	}
+	if got := game.CommentsByPly(); !reflect.DeepEqual(got, want) {
+		t.Errorf("CommentsByPly() = %q, want %q", got, want)
+	}
+
+	plain, err := chessnote.ParseString("1. e4 e5 *")
+	if err != nil {
+		t.Fatalf("ParseString() failed: %v", err)
+	}
+	if got := plain.CommentsByPly(); len(got) != 0 {
+		t.Errorf("CommentsByPly() = %q, want an empty map", got)
+	}
+}
+
+func TestGameVariationsAsGames(t *testing.T) {
+	t.Parallel()
+	game, err := chessnote.ParseString(`[White "Morphy"]
+[PlyCount "4"]
+1. e4 e5 (1... c5 2. Nf3 (2. c3 d5) d6) 2. Nf3 (2. f4 exf4) Nc6 1-0`)
+	if err != nil {
+		t.Fatalf("ParseString() failed: %v", err)
+	}
+
+	games := game.VariationsAsGames()
+	want := []struct {
+		fen   string
+		moves []string
+	}{
+		{"rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1", []string{"c5", "Nf3", "d6"}},
+		{"rnbqkbnr/pp1ppppp/8/2p5/4P3/8/PPPP1PPP/RNBQKBNR w KQkq c6 0 2", []string{"c3", "d5"}},
+		{"rnbqkbnr/pppp1ppp/8/4p3/4P3/8/PPPP1PPP/RNBQKBNR w KQkq e6 0 2", []string{"f4", "exf4"}},
+	}
+	if len(games) != len(want) {
+		t.Fatalf("got %d games, want %d", len(games), len(want))
+	}
+	for i, w := range want {
+		g := games[i]
+		var moves []string
+		for _, m := range g.Moves {
+			moves = append(moves, m.String())
+		}
+		if !reflect.DeepEqual(moves, w.moves) {
+			t.Errorf("game %d: got moves %v, want %v", i, moves, w.moves)
+		}
+		if g.Tags["FEN"] != w.fen {
+			t.Errorf("game %d: got FEN tag %q, want %q", i, g.Tags["FEN"], w.fen)
+		}
+		if g.Tags["SetUp"] != "1" || g.Tags["White"] != "Morphy" {
+			t.Errorf("game %d: got tags %v, want SetUp and the original tags", i, g.Tags)
+		}
+		if g.Result != "*" || g.Tags["Result"] != "*" {
+			t.Errorf("game %d: got result %q (tag %q), want %q", i, g.Result, g.Tags["Result"], "*")
+		}
+		if want := strconv.Itoa(len(w.moves)); g.Tags["PlyCount"] != want {
+			t.Errorf("game %d: got PlyCount tag %q, want %q", i, g.Tags["PlyCount"], want)
+		}
+		if _, err := g.Positions(); err != nil {
+			t.Errorf("game %d: expected the game to replay from its FEN, got %v", i, err)
+		}
+	}
+
+	if len(games[0].Moves[1].Variations) != 1 {
+		t.Errorf("expected the nested variation to be kept in its parent line")
+	}
+	games[0].Moves[0].IsCheck = true
+	if game.Moves[1].Variations[0][0].IsCheck {
+		t.Errorf("modifying a variation game changed the original game")
+	}
+
+	plain, err := chessnote.ParseString("1. e4 e5 *")
+	if err != nil {
+		t.Fatalf("ParseString() failed: %v", err)
+	}
+	if games := plain.VariationsAsGames(); len(games) != 0 {
+		t.Errorf("got %d games for a game without variations, want 0", len(games))
+	}
+}
+
+func TestGameOpeningLine(t *testing.T) {
+	t.Parallel()
+	game, err := chessnote.ParseString(`1. e4 {Best by test} e5 (1... c5 2. Nf3) 2. Nf3 $1 Nc6 3. Bb5 a6 *`)
+	if err != nil {
+		t.Fatalf("ParseString() failed: %v", err)
+	}
+	tests := []struct {
+		name   string
+		maxPly int
+		want   []string
+	}{
+		{"shorter than the game", 3, []string{"e4", "e5", "Nf3"}},
+		{"exactly the game", 6, []string{"e4", "e5", "Nf3", "Nc6", "Bb5", "a6"}},
+		{"longer than the game", 20, []string{"e4", "e5", "Nf3", "Nc6", "Bb5", "a6"}},
+		{"zero plies", 0, nil},
+		{"negative plies", -1, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line := game.OpeningLine(tt.maxPly)
+			var got []string
+			for _, m := range line {
+				if m.Comments != nil || m.NAGs != nil || m.Variations != nil {
+					t.Errorf("move %s kept its annotations: %+v", m, m)
+				}
+				got = append(got, m.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("OpeningLine(%d) = %v, want %v", tt.maxPly, got, tt.want)
+			}
+		})
+	}
+
+	if len(game.Moves[0].Comments) != 1 || len(game.Moves[1].Variations) != 1 || len(game.Moves[2].NAGs) != 1 {
+		t.Errorf("OpeningLine() changed the annotations of the original game")
+	}
+}
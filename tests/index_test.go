@@ -0,0 +1,227 @@
+package chessnote_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/YashBhalodi/chessnote"
+)
+
+func TestPositionIndex(t *testing.T) {
+	t.Parallel()
+	pgns := []string{
+		"1. e4 Nf6 2. Nc3 Nc6 *",
+		"1. Nc3 Nf6 2. e4 Nc6 *",
+		"1. d4 d5 2. Nf3 Nf6 3. Ng1 Ng8 4. Nf3 *",
+	}
+	var index chessnote.PositionIndex
+	for _, pgn := range pgns {
+		game, err := chessnote.ParseString(pgn)
+		if err != nil {
+			t.Fatalf("ParseString() failed: %v", err)
+		}
+		if err := index.Add(game); err != nil {
+			t.Fatalf("Add() failed: %v", err)
+		}
+	}
+	if index.Len() != len(pgns) {
+		t.Errorf("Len() = %d, want %d", index.Len(), len(pgns))
+	}
+
+	tests := []struct {
+		name      string
+		fen       string
+		wantGames []int
+		wantPlies []int
+	}{
+		{
+			name:      "starting position",
+			fen:       "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1",
+			wantGames: []int{0, 1, 2},
+			wantPlies: []int{0, 0, 0},
+		},
+		{
+			name:      "transposition with different counters",
+			fen:       "r1bqkb1r/pppppppp/2n2n2/8/4P3/2N5/PPPP1PPP/R1BQKBNR w KQkq - 4 9",
+			wantGames: []int{0, 1},
+			wantPlies: []int{4, 4},
+		},
+		{
+			name:      "irrelevant en passant square",
+			fen:       "rnbqkb1r/pppppppp/5n2/8/4P3/2N5/PPPP1PPP/R1BQKBNR b KQkq e3 0 2",
+			wantGames: []int{0, 1},
+			wantPlies: []int{3, 3},
+		},
+		{
+			name:      "repeated position recorded once",
+			fen:       "rnbqkbnr/ppp1pppp/8/3p4/3P4/5N2/PPP1PPPP/RNBQKB1R b KQkq - 1 2",
+			wantGames: []int{2},
+			wantPlies: []int{3},
+		},
+		{
+			name: "never reached",
+			fen:  "4k3/8/8/8/8/8/8/4K3 w - - 0 1",
+		},
+		{
+			name: "invalid FEN",
+			fen:  "not a fen",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			refs := index.Find(tt.fen)
+			if len(refs) != len(tt.wantGames) {
+				t.Fatalf("Find() returned %d games, want %d: %+v", len(refs), len(tt.wantGames), refs)
+			}
+			for i, ref := range refs {
+				if ref.Index != tt.wantGames[i] || ref.Ply != tt.wantPlies[i] {
+					t.Errorf("Find()[%d] = game %d ply %d, want game %d ply %d", i, ref.Index, ref.Ply, tt.wantGames[i], tt.wantPlies[i])
+				}
+				if ref.Game == nil {
+					t.Errorf("Find()[%d].Game is nil", i)
+				}
+			}
+		})
+	}
+}
+
+func TestPositionIndexIllegalMove(t *testing.T) {
+	t.Parallel()
+	game, err := chessnote.ParseString("1. e4 e5 2. Ke3 *")
+	if err != nil {
+		t.Fatalf("ParseString() failed: %v", err)
+	}
+	var index chessnote.PositionIndex
+	if err := index.Add(game); err == nil {
+		t.Error("Add() expected an error for an illegal move, but got nil")
+	}
+	refs := index.Find("rnbqkbnr/pppp1ppp/8/4p3/4P3/8/PPPP1PPP/RNBQKBNR w KQkq e6 0 2")
+	if len(refs) != 1 || refs[0].Ply != 2 {
+		t.Errorf("Find() = %+v, want the position before the illegal move at ply 2", refs)
+	}
+}
+
+func TestGameHashes(t *testing.T) {
+	t.Parallel()
+	parse := func(pgn string) []uint64 {
+		t.Helper()
+		game, err := chessnote.ParseString(pgn)
+		if err != nil {
+			t.Fatalf("ParseString() failed: %v", err)
+		}
+		return game.Hashes()
+	}
+
+	a := parse("1. e4 e5 2. Nf3 Nc6 3. Bb5 *")
+	b := parse("1. Nf3 Nc6 2. e4 e5 3. Bc4 *")
+	if len(a) != 5 || len(b) != 5 {
+		t.Fatalf("got %d and %d hashes, want 5 each", len(a), len(b))
+	}
+	if a[0] == b[0] {
+		t.Errorf("expected different hashes after different first moves")
+	}
+	if a[3] != b[3] {
+		t.Errorf("expected the transposed games to share the hash after ply 4")
+	}
+	if a[4] == b[4] {
+		t.Errorf("expected different hashes after 3. Bb5 and 3. Bc4")
+	}
+
+	c := parse("1. e4 e5 2. Nf3 Nf6 *")
+	if a[0] != c[0] || a[1] != c[1] || a[2] != c[2] || a[3] == c[3] {
+		t.Errorf("expected the games to share exactly their first 3 hashes")
+	}
+
+	if got := parse("1. e4 e5 2. Ke3 Nc6 *"); len(got) != 2 {
+		t.Errorf("got %d hashes for a game with an illegal third ply, want 2", len(got))
+	}
+}
+
+func TestGameContainsPosition(t *testing.T) {
+	t.Parallel()
+	game, err := chessnote.ParseString("1. e4 e5 2. Nf3 Nc6 3. Ng1 Nb8 4. Nf3 Nc6 *")
+	if err != nil {
+		t.Fatalf("ParseString() failed: %v", err)
+	}
+	tests := []struct {
+		name    string
+		fen     string
+		want    bool
+		wantPly int
+	}{
+		{"initial position", "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1", true, 0},
+		{"en passant square that cannot be used", "rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1", true, 1},
+		{"without the en passant square", "rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq - 0 1", true, 1},
+		{"move counters are ignored", "rnbqkbnr/pppp1ppp/8/4p3/4P3/8/PPPP1PPP/RNBQKBNR w KQkq - 7 30", true, 2},
+		{"first occurrence of a repeated position", "r1bqkbnr/pppp1ppp/2n5/4p3/4P3/5N2/PPPP1PPP/RNBQKB1R w KQkq - 2 3", true, 4},
+		{"wrong side to move", "rnbqkbnr/pppp1ppp/8/4p3/4P3/8/PPPP1PPP/RNBQKBNR b KQkq - 0 2", false, 0},
+		{"wrong castling rights", "rnbqkbnr/pppp1ppp/8/4p3/4P3/8/PPPP1PPP/RNBQKBNR w Kkq - 0 2", false, 0},
+		{"never reached", "rnbqkbnr/pppppppp/8/8/3P4/8/PPP1PPPP/RNBQKBNR b KQkq - 0 1", false, 0},
+		{"invalid FEN", "not a fen", false, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ply := game.ContainsPosition(tt.fen)
+			if got != tt.want || ply != tt.wantPly {
+				t.Errorf("ContainsPosition() = %v, %d, want %v, %d", got, ply, tt.want, tt.wantPly)
+			}
+		})
+	}
+}
+
+func TestGameTranspositions(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		pgn       string
+		want      [][]int
+		wantPaths [][][]int
+	}{
+		{
+			name:      "variations transposing into the mainline",
+			pgn:       "1. e4 e5 2. Nf3 (2. Nc3 Nc6 3. Nf3 Nf6) 2... Nc6 3. Nc3 (3. Bb5) 3... Nf6 *",
+			want:      [][]int{{5, 8}, {6, 10}},
+			wantPaths: [][][]int{{{2, 0, 2}, {4}}, {{2, 0, 3}, {5}}},
+		},
+		{
+			name:      "two variations transposing into each other",
+			pgn:       "1. e4 (1. d4 d5 2. c4) (1. c4 d5 2. d4) 1... e5 *",
+			want:      [][]int{{3, 6}},
+			wantPaths: [][][]int{{{0, 0, 2}, {0, 1, 2}}},
+		},
+		{
+			name: "repetition along one line",
+			pgn:  "1. Nf3 Nf6 2. Ng1 Ng8 3. Nf3 Nf6 *",
+			want: nil,
+		},
+		{
+			name: "no variations",
+			pgn:  "1. e4 e5 2. Nf3 *",
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game, err := chessnote.ParseString(tt.pgn)
+			if err != nil {
+				t.Fatalf("ParseString() failed: %v", err)
+			}
+			got := game.Transpositions()
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Transpositions() = %v, want %v", got, tt.want)
+			}
+			refs := game.FindMoves(func(chessnote.Move) bool { return true })
+			var paths [][][]int
+			for _, group := range got {
+				var groupPaths [][]int
+				for _, node := range group {
+					groupPaths = append(groupPaths, refs[node].Path)
+				}
+				paths = append(paths, groupPaths)
+			}
+			if !reflect.DeepEqual(paths, tt.wantPaths) {
+				t.Errorf("Transpositions() paths = %v, want %v", paths, tt.wantPaths)
+			}
+		})
+	}
+}
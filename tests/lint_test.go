@@ -0,0 +1,80 @@
+package chessnote_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/YashBhalodi/chessnote"
+)
+
+func TestGameRedundantDisambiguations(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		pgn       string
+		wantPaths [][]int
+		wantPlies []int
+	}{
+		{
+			name: "necessary file hint",
+			pgn:  "1. d4 d5 2. Nf3 Nf6 3. Nbd2 *",
+		},
+		{
+			name:      "unnecessary file hint",
+			pgn:       "1. d4 d5 2. Nbd2 *",
+			wantPaths: [][]int{{2}},
+			wantPlies: []int{3},
+		},
+		{
+			name:      "unnecessary rank hint",
+			pgn:       "1. Nf3 d5 2. N1c3 *",
+			wantPaths: [][]int{{2}},
+			wantPlies: []int{3},
+		},
+		{
+			name:      "unnecessary a-file hint",
+			pgn:       "1. a4 e5 2. Ra3 Nc6 3. Raa2 *",
+			wantPaths: [][]int{{4}},
+			wantPlies: []int{5},
+		},
+		{
+			name:      "other piece is pinned",
+			pgn:       "1. e4 e5 2. d3 Bb4+ 3. Nc3 d6 4. Nge2 *",
+			wantPaths: [][]int{{6}},
+			wantPlies: []int{7},
+		},
+		{
+			name:      "inside a variation",
+			pgn:       "1. d4 d5 2. Nf3 (2. Nbd2 Nf6) 2... Nf6 *",
+			wantPaths: [][]int{{2, 0, 0}},
+			wantPlies: []int{3},
+		},
+		{
+			name: "pawn captures are never reported",
+			pgn:  "1. e4 d5 2. exd5 *",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			game, err := chessnote.ParseString(tt.pgn)
+			if err != nil {
+				t.Fatalf("ParseString() failed: %v", err)
+			}
+			refs := game.RedundantDisambiguations()
+			var gotPaths [][]int
+			var gotPlies []int
+			for _, ref := range refs {
+				gotPaths = append(gotPaths, ref.Path)
+				gotPlies = append(gotPlies, ref.Ply)
+			}
+			if !reflect.DeepEqual(gotPaths, tt.wantPaths) {
+				t.Errorf("got paths %v, want %v", gotPaths, tt.wantPaths)
+			}
+			if !reflect.DeepEqual(gotPlies, tt.wantPlies) {
+				t.Errorf("got plies %v, want %v", gotPlies, tt.wantPlies)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package chessnote_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/YashBhalodi/chessnote"
@@ -12,6 +13,9 @@ func FuzzParse(f *testing.F) {
 	f.Add("[Event \"F/S Return Match\"]")
 	f.Add("1. e4 e5 2. Nf3 Nc6 *")
 	f.Add("[White \"Kasparov, Garry\"] 1/2-1/2")
+	f.Add(") 1. e4 *")
+	f.Add("] 1. e4 *")
+	f.Add(strings.Repeat("9", 4096) + ". e4 *")
 
 	f.Fuzz(func(t *testing.T, data string) {
 		// The parser should handle any string input without panicking.
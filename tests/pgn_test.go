@@ -1,8 +1,13 @@
 package chessnote_test
 
 import (
+	"errors"
+	"io"
+	"os"
+	"reflect"
 	"strings"
 	"testing"
+	"testing/iotest"
 
 	"github.com/YashBhalodi/chessnote"
 )
@@ -47,6 +52,15 @@ func TestSplitMultiGame(t *testing.T) {
 			},
 			wantLen: 2,
 		},
+		{
+			name: "two games with classic mac newlines",
+			pgn:  "[Event \"1\"]\r1. e4 *\r\r[Event \"2\"]\r1. d4 *",
+			want: []string{
+				"[Event \"1\"]\n1. e4 *",
+				"[Event \"2\"]\n1. d4 *",
+			},
+			wantLen: 2,
+		},
 		{
 			name:    "empty with only whitespace",
 			pgn:     "  \n \r\n  ",
@@ -62,6 +76,51 @@ func TestSplitMultiGame(t *testing.T) {
 			},
 			wantLen: 2,
 		},
+		{
+			name: "comment after the result stays with its game",
+			pgn:  "[Event \"1\"]\n1. e4 *\n{Annotated by\nhand}\n\n[Event \"2\"]\n1. d4 *",
+			want: []string{
+				"[Event \"1\"]\n1. e4 *\n{Annotated by\nhand}",
+				"[Event \"2\"]\n1. d4 *",
+			},
+			wantLen: 2,
+		},
+		{
+			name: "event tag quoted inside a comment",
+			pgn:  "[Event \"1\"]\n1. e4 * {Replayed at\n[Event \"Rapid\"] later}\n[Event \"2\"]\n1. d4 *",
+			want: []string{
+				"[Event \"1\"]\n1. e4 * {Replayed at\n[Event \"Rapid\"] later}",
+				"[Event \"2\"]\n1. d4 *",
+			},
+			wantLen: 2,
+		},
+		{
+			name: "movetext right after the tags",
+			pgn:  "[Event \"1\"]\n[Result \"1-0\"]\n1. e4 {Opening\n[Event \"Rapid\"]} e5 1-0\n[Event \"2\"]\n1. d4 *",
+			want: []string{
+				"[Event \"1\"]\n[Result \"1-0\"]\n1. e4 {Opening\n[Event \"Rapid\"]} e5 1-0",
+				"[Event \"2\"]\n1. d4 *",
+			},
+			wantLen: 2,
+		},
+		{
+			name: "tags and movetext on one line",
+			pgn:  "[Event \"1\"] [Result \"1-0\"] 1. e4 {Opening\n[Event \"Rapid\"]} e5 1-0\n[Event \"2\"][Result \"*\"] 1. d4 *",
+			want: []string{
+				"[Event \"1\"] [Result \"1-0\"] 1. e4 {Opening\n[Event \"Rapid\"]} e5 1-0",
+				"[Event \"2\"][Result \"*\"] 1. d4 *",
+			},
+			wantLen: 2,
+		},
+		{
+			name: "brace inside a tag value",
+			pgn:  "[Event \"1 {\\\"x\\\"]\"]\n1. e4 *\n[Event \"2\"]\n1. d4 *",
+			want: []string{
+				"[Event \"1 {\\\"x\\\"]\"]\n1. e4 *",
+				"[Event \"2\"]\n1. d4 *",
+			},
+			wantLen: 2,
+		},
 	}
 
 	for _, tt := range tests {
@@ -79,6 +138,120 @@ func TestSplitMultiGame(t *testing.T) {
 					t.Errorf("game %d mismatch:\ngot:\n%s\nwant:\n%s", i, got[i], tt.want[i])
 				}
 			}
+			if n, err := chessnote.CountGames(strings.NewReader(tt.pgn)); n != tt.wantLen || err != nil {
+				t.Errorf("CountGames() = %d, %v, want %d, nil", n, err, tt.wantLen)
+			}
+		})
+	}
+}
+
+func TestCountGames(t *testing.T) {
+	t.Parallel()
+	data, err := os.ReadFile("../examples/multiple-game-pgn/Kasparov.pgn")
+	if err != nil {
+		t.Fatalf("failed to read PGN file: %v", err)
+	}
+
+	inputs := map[string]string{
+		"fixture":                   string(data),
+		"fixture with crlf":         strings.ReplaceAll(string(data), "\n", "\r\n"),
+		"fixture with lone cr":      strings.ReplaceAll(string(data), "\n", "\r"),
+		"text before the first tag": "Exported by hand\n\n" + string(data),
+		"no event tags":             "1. e4 e5 *",
+		"long line":                 "[Event \"1\"]\n{" + strings.Repeat("x", 100000) + "}\n[Event \"2\"]\n1. d4 *",
+	}
+	for name, pgn := range inputs {
+		got, err := chessnote.CountGames(strings.NewReader(pgn))
+		if err != nil {
+			t.Fatalf("%s: CountGames() failed: %v", name, err)
+		}
+		if want := len(chessnote.SplitMultiGame(pgn)); got != want {
+			t.Errorf("%s: CountGames() = %d, want %d", name, got, want)
+		}
+	}
+
+	if got, err := chessnote.CountGames(strings.NewReader(string(data))); got != 68 || err != nil {
+		t.Errorf("CountGames() = %d, %v, want 68 games in the fixture", got, err)
+	}
+
+	readErr := errors.New("read failed")
+	r := io.MultiReader(strings.NewReader("[Event \"1\"]\n1. e4 *\n"), iotest.ErrReader(readErr))
+	if _, err := chessnote.CountGames(r); !errors.Is(err, readErr) {
+		t.Errorf("CountGames() error = %v, want %v", err, readErr)
+	}
+}
+
+func TestSplitMultiGameFunc(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		pgn        string
+		isBoundary func(line string) bool
+		want       []string
+	}{
+		{
+			name:       "split on White tags",
+			pgn:        "[White \"A\"]\n1. e4 *\n[White \"B\"]\n1. d4 *",
+			isBoundary: func(line string) bool { return strings.HasPrefix(line, "[White ") },
+			want:       []string{"[White \"A\"]\n1. e4 *", "[White \"B\"]\n1. d4 *"},
+		},
+		{
+			name:       "split on a comment marker",
+			pgn:        "{=== Game 1 ===}\n1. e4 *\n{=== Game 2 ===}\n1. d4 *",
+			isBoundary: func(line string) bool { return strings.HasPrefix(line, "{===") },
+			want:       []string{"{=== Game 1 ===}\n1. e4 *", "{=== Game 2 ===}\n1. d4 *"},
+		},
+		{
+			name:       "boundary lines are trimmed",
+			pgn:        "  [Event \"1\"]\n1. e4 *\n\t[Event \"2\"]  \n1. d4 *",
+			isBoundary: func(line string) bool { return line == "[Event \"2\"]" },
+			want:       []string{"[Event \"1\"]\n1. e4 *", "[Event \"2\"]  \n1. d4 *"},
+		},
+		{
+			name:       "never a boundary",
+			pgn:        "[Event \"1\"]\n1. e4 *\n[Event \"2\"]\n1. d4 *",
+			isBoundary: func(string) bool { return false },
+			want:       []string{"[Event \"1\"]\n1. e4 *\n[Event \"2\"]\n1. d4 *"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := chessnote.SplitMultiGameFunc(tt.pgn, tt.isBoundary)
+			if len(got) != len(tt.want) {
+				t.Fatalf("SplitMultiGameFunc() got %d games, want %d: %q", len(got), len(tt.want), got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("game %d mismatch:\ngot:\n%s\nwant:\n%s", i, got[i], tt.want[i])
+				}
+			}
 		})
 	}
 }
+
+func TestParseClassicMacLineEndings(t *testing.T) {
+	t.Parallel()
+	pgn := "[Event \"1\"]\r\r1. e4 ; King's pawn\re5 *\r\r[Event \"2\"]\r\r1. d4 d5 1/2-1/2\r"
+
+	games := chessnote.SplitMultiGame(pgn)
+	if len(games) != 2 {
+		t.Fatalf("SplitMultiGame() returned %d games, want 2", len(games))
+	}
+
+	// The scanner handles lone '\r' itself, without the normalization done
+	// by SplitMultiGame.
+	for _, input := range []string{strings.Split(pgn, "\r\r[")[0], games[0]} {
+		game, err := chessnote.ParseString(input)
+		if err != nil {
+			t.Fatalf("ParseString(%q) failed: %v", input, err)
+		}
+		if len(game.Moves) != 2 || game.Tags["Event"] != "1" {
+			t.Errorf("ParseString(%q) = %d moves and tags %v, want 2 moves and Event 1", input, len(game.Moves), game.Tags)
+		}
+		if want := []string{"King's pawn"}; !reflect.DeepEqual(game.Moves[0].Comments, want) {
+			t.Errorf("got comments %q, want %q", game.Moves[0].Comments, want)
+		}
+	}
+}
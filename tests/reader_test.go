@@ -0,0 +1,328 @@
+package chessnote_test
+
+import (
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/YashBhalodi/chessnote"
+)
+
+func TestGameReader(t *testing.T) {
+	t.Parallel()
+	pgn := "[Event \"1\"]\n1. e4 e5 1-0\n\n[Event \"2\"]\n1. d4 d5 2. c4 0-1\n\n[Event \"3\"]\n1. c4 *\n"
+	gr := chessnote.NewGameReader(strings.NewReader(pgn))
+
+	wantEvents := []string{"1", "2", "3"}
+	wantMoves := []int{2, 3, 1}
+	for i := range wantEvents {
+		game, err := gr.Next()
+		if err != nil {
+			t.Fatalf("game %d: Next() error = %v", i+1, err)
+		}
+		if game.Tags["Event"] != wantEvents[i] {
+			t.Errorf("game %d: got Event %q, want %q", i+1, game.Tags["Event"], wantEvents[i])
+		}
+		if len(game.Moves) != wantMoves[i] {
+			t.Errorf("game %d: got %d moves, want %d", i+1, len(game.Moves), wantMoves[i])
+		}
+		if game.Trailing {
+			t.Errorf("game %d: expected Trailing to be false for games read by a GameReader", i+1)
+		}
+	}
+	if _, err := gr.Next(); err != io.EOF {
+		t.Errorf("expected io.EOF after the last game, got %v", err)
+	}
+}
+
+func TestGameReaderMatchesSplitMultiGame(t *testing.T) {
+	t.Parallel()
+	data, err := os.ReadFile("../examples/multiple-game-pgn/Kasparov.pgn")
+	if err != nil {
+		t.Fatalf("failed to read PGN file: %v", err)
+	}
+	want := len(chessnote.SplitMultiGame(string(data)))
+
+	gr := chessnote.NewGameReader(strings.NewReader(string(data)))
+	got := 0
+	for {
+		_, err := gr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("game %d: Next() error = %v", got+1, err)
+		}
+		got++
+	}
+	if got != want {
+		t.Errorf("GameReader read %d games, SplitMultiGame found %d", got, want)
+	}
+}
+
+func TestGameReaderStreamsFromPipe(t *testing.T) {
+	t.Parallel()
+	pr, pw := io.Pipe()
+	firstGameRead := make(chan struct{})
+	writerErr := make(chan error, 1)
+
+	go func() {
+		// Write exactly one game, then hold the stream open until the
+		// reader has yielded it.
+		if _, err := io.WriteString(pw, "[Event \"1\"]\n1. e4 e5 1-0\n"); err != nil {
+			writerErr <- err
+			return
+		}
+		select {
+		case <-firstGameRead:
+		case <-time.After(5 * time.Second):
+			pw.CloseWithError(io.ErrUnexpectedEOF)
+			writerErr <- nil
+			return
+		}
+		_, err := io.WriteString(pw, "{Late comment}\n\n[Event \"2\"]\n1. d4 d5 0-1\n")
+		pw.Close()
+		writerErr <- err
+	}()
+
+	gr := chessnote.NewGameReader(pr)
+	type result struct {
+		game *chessnote.Game
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		game, err := gr.Next()
+		done <- result{game, err}
+	}()
+	var first *chessnote.Game
+	select {
+	case r := <-done:
+		if r.err != nil {
+			t.Fatalf("first Next() error = %v", r.err)
+		}
+		first = r.game
+	case <-time.After(2 * time.Second):
+		t.Fatal("first Next() did not return while the stream was held open after the game")
+	}
+	close(firstGameRead)
+	if first.Tags["Event"] != "1" || len(first.Moves) != 2 || first.Result != "1-0" {
+		t.Errorf("unexpected first game: %+v", first)
+	}
+
+	second, err := gr.Next()
+	if err != nil {
+		t.Fatalf("second Next() error = %v", err)
+	}
+	if second.Tags["Event"] != "2" || len(second.Moves) != 2 {
+		t.Errorf("unexpected second game: %+v", second)
+	}
+	if !reflect.DeepEqual(first.ResultComments, []string{"Late comment"}) {
+		t.Errorf("got first game result comments %q, want the comment read with the second game", first.ResultComments)
+	}
+	if _, err := gr.Next(); err != io.EOF {
+		t.Errorf("expected io.EOF after the last game, got %v", err)
+	}
+	if err := <-writerErr; err != nil {
+		t.Errorf("writer error: %v", err)
+	}
+}
+
+func TestGameReaderErrorIsSticky(t *testing.T) {
+	t.Parallel()
+	gr := chessnote.NewGameReader(strings.NewReader("1. e4 ] *\n\n1. d4 *"))
+	_, err := gr.Next()
+	if err == nil {
+		t.Fatal("expected an error for malformed movetext, but got nil")
+	}
+	if _, again := gr.Next(); again != err {
+		t.Errorf("expected the same error on the next call, got %v", again)
+	}
+}
+
+func TestCommentsAcrossGameBoundary(t *testing.T) {
+	t.Parallel()
+	pgn := "[Event \"1\"]\n\n1. e4 e5 1-0 {White resigned? No, Black did.}\n; Source: club archive\n\n" +
+		"[Event \"2\"]\n\n{Second game} 1. d4 *\n"
+	wantResultComments := [][]string{{"White resigned? No, Black did.", "Source: club archive"}, nil}
+	wantComments := [][]string{nil, {"Second game"}}
+
+	check := func(t *testing.T, i int, game *chessnote.Game) {
+		t.Helper()
+		if !reflect.DeepEqual(game.ResultComments, wantResultComments[i]) {
+			t.Errorf("game %d: got result comments %q, want %q", i+1, game.ResultComments, wantResultComments[i])
+		}
+		if !reflect.DeepEqual(game.Comments, wantComments[i]) {
+			t.Errorf("game %d: got game comments %q, want %q", i+1, game.Comments, wantComments[i])
+		}
+	}
+
+	t.Run("GameReader", func(t *testing.T) {
+		// A game's result comments are read by the call to Next after the
+		// one that returned it, so check the games once all are read.
+		gr := chessnote.NewGameReader(strings.NewReader(pgn))
+		var games []*chessnote.Game
+		for i := range wantComments {
+			game, err := gr.Next()
+			if err != nil {
+				t.Fatalf("game %d: Next() error = %v", i+1, err)
+			}
+			games = append(games, game)
+		}
+		if _, err := gr.Next(); err != io.EOF {
+			t.Errorf("expected io.EOF after the last game, got %v", err)
+		}
+		for i, game := range games {
+			check(t, i, game)
+		}
+	})
+
+	t.Run("SplitMultiGame", func(t *testing.T) {
+		games := chessnote.SplitMultiGame(pgn)
+		if len(games) != len(wantComments) {
+			t.Fatalf("SplitMultiGame() got %d games, want %d", len(games), len(wantComments))
+		}
+		for i, s := range games {
+			game, err := chessnote.ParseString(s)
+			if err != nil {
+				t.Fatalf("game %d: ParseString() failed: %v", i+1, err)
+			}
+			if game.Trailing {
+				t.Errorf("game %d: expected Trailing to be false when only comments follow the result", i+1)
+			}
+			check(t, i, game)
+		}
+	})
+
+	t.Run("Parse stops before the next game", func(t *testing.T) {
+		game, err := chessnote.ParseString(pgn)
+		if err != nil {
+			t.Fatalf("ParseString() failed: %v", err)
+		}
+		if !game.Trailing {
+			t.Error("expected Trailing to be set when a second game follows")
+		}
+		check(t, 0, game)
+	})
+}
+
+func TestParseWithSharedTags(t *testing.T) {
+	t.Parallel()
+	pgn := `[Event "Club Championship"]
+[Site "London"]
+[Round "1"]
+
+1. e4 e5 2. Nf3 1-0
+1. d4 d5 0-1
+
+[Round "2"]
+[White "Guest"]
+1. c4 1/2-1/2
+1. Nf3 *
+`
+	games, err := chessnote.ParseWithSharedTags(pgn)
+	if err != nil {
+		t.Fatalf("ParseWithSharedTags() failed: %v", err)
+	}
+	want := []struct {
+		tags  map[string]string
+		moves int
+	}{
+		{map[string]string{"Event": "Club Championship", "Site": "London", "Round": "1"}, 3},
+		{map[string]string{"Event": "Club Championship", "Site": "London", "Round": "1"}, 2},
+		{map[string]string{"Event": "Club Championship", "Site": "London", "Round": "2", "White": "Guest"}, 1},
+		{map[string]string{"Event": "Club Championship", "Site": "London", "Round": "1"}, 1},
+	}
+	if len(games) != len(want) {
+		t.Fatalf("got %d games, want %d", len(games), len(want))
+	}
+	for i, w := range want {
+		if !reflect.DeepEqual(games[i].Tags, w.tags) {
+			t.Errorf("game %d: got tags %v, want %v", i+1, games[i].Tags, w.tags)
+		}
+		if len(games[i].Moves) != w.moves {
+			t.Errorf("game %d: got %d moves, want %d", i+1, len(games[i].Moves), w.moves)
+		}
+	}
+
+	games[1].Tags["Site"] = "Paris"
+	if games[0].Tags["Site"] != "London" || games[3].Tags["Site"] != "London" {
+		t.Error("games share a tag map")
+	}
+}
+
+func TestParseWithSharedTagsPerGameTags(t *testing.T) {
+	t.Parallel()
+	pgn := `[Event "Club Championship"]
+[Result "1-0"]
+[PlyCount "3"]
+[SetUp "1"]
+[FEN "4k3/8/8/8/8/8/4P3/4K3 w - - 0 1"]
+
+1. e4 Kd7 2. e5 1-0
+1. d4 0-1
+`
+	games, err := chessnote.ParseWithSharedTags(pgn)
+	if err != nil {
+		t.Fatalf("ParseWithSharedTags() failed: %v", err)
+	}
+	if len(games) != 2 {
+		t.Fatalf("got %d games, want 2", len(games))
+	}
+	if got := games[0].Tags["Result"]; got != "1-0" {
+		t.Errorf("game 1: got Result tag %q, want %q", got, "1-0")
+	}
+	want := map[string]string{"Event": "Club Championship"}
+	if !reflect.DeepEqual(games[1].Tags, want) {
+		t.Errorf("game 2: got tags %v, want %v", games[1].Tags, want)
+	}
+	if !games[1].ResultConsistent() {
+		t.Errorf("game 2: result %q disagrees with Result tag %q", games[1].Result, games[1].Tags["Result"])
+	}
+	if errs := games[1].Validate(); errs != nil {
+		t.Errorf("game 2: Validate() = %v, want no errors", errs)
+	}
+}
+
+func TestParseWithSharedTagsErrors(t *testing.T) {
+	t.Parallel()
+	games, err := chessnote.ParseWithSharedTags("")
+	if err != nil || len(games) != 0 {
+		t.Errorf("ParseWithSharedTags(\"\") = %v, %v, want no games and no error", games, err)
+	}
+
+	_, err = chessnote.ParseWithSharedTags("[Event \"E\"]\n\n1. e4 *\n1. e9 *")
+	if err == nil || !strings.Contains(err.Error(), "game 2") {
+		t.Errorf("ParseWithSharedTags() error = %v, want an error naming game 2", err)
+	}
+}
+
+func TestParserReset(t *testing.T) {
+	t.Parallel()
+	p := chessnote.NewParser(strings.NewReader("[FEN \"4k3/8/8/8/8/8/4P3/4K3 b - - 0 30\"]\n30... Kd7 31. e4 * 1. d4 *"), chessnote.WithLaxParsing())
+	first, err := p.Parse()
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	if !first.Trailing {
+		t.Fatalf("expected unread input after the first game")
+	}
+
+	p.Reset(strings.NewReader("[Event \"Second\"]\n1. e4 e5"))
+	second, err := p.Parse()
+	if err != nil {
+		t.Fatalf("Parse() after Reset failed: %v", err)
+	}
+	if second.Tags["Event"] != "Second" || len(second.Moves) != 2 || second.Trailing {
+		t.Errorf("got game %+v, want only the game from the new reader", second)
+	}
+	if len(second.ParseWarnings) != 0 {
+		t.Errorf("got warnings %v, want none carried over from the previous game", second.ParseWarnings)
+	}
+	if p.Config().Strict {
+		t.Error("Reset() changed the parser configuration")
+	}
+}
@@ -0,0 +1,308 @@
+package chessnote_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/YashBhalodi/chessnote"
+)
+
+func TestParseOutcome(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		s    string
+		want chessnote.Outcome
+	}{
+		{"white wins", "1-0", chessnote.OutcomeWhiteWins},
+		{"black wins", "0-1", chessnote.OutcomeBlackWins},
+		{"draw", "1/2-1/2", chessnote.OutcomeDraw},
+		{"ongoing", "*", chessnote.OutcomeOngoing},
+		{"padded", " 1-0 ", chessnote.OutcomeWhiteWins},
+		{"padded with tabs and newlines", "\t0-1\n", chessnote.OutcomeBlackWins},
+		{"abbreviated draw", "1/2", chessnote.OutcomeUnknown},
+		{"deprecated RR", "RR", chessnote.OutcomeUnknown},
+		{"question marks", "??", chessnote.OutcomeUnknown},
+		{"empty", "", chessnote.OutcomeUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := chessnote.ParseOutcome(tt.s); got != tt.want {
+				t.Errorf("ParseOutcome(%q) = %v, want %v", tt.s, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGameOutcome(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		pgn  string
+		opts []chessnote.ParserOption
+		want chessnote.Outcome
+	}{
+		{"movetext and tag agree", `[Result "1-0"] 1. e4 1-0`, nil, chessnote.OutcomeWhiteWins},
+		{"padded tag agrees with token", `[Result " 1/2-1/2 "] 1. e4 1/2-1/2`, nil, chessnote.OutcomeDraw},
+		{"token only", `1. e4 0-1`, nil, chessnote.OutcomeBlackWins},
+		{"tag only", `[Result " 0-1 "] 1. e4`, []chessnote.ParserOption{chessnote.WithLaxParsing()}, chessnote.OutcomeBlackWins},
+		{"unrecognized tag", `[Result "1/2"] 1. e4`, []chessnote.ParserOption{chessnote.WithLaxParsing()}, chessnote.OutcomeUnknown},
+		{"in progress", `[Result "*"] 1. e4 *`, nil, chessnote.OutcomeOngoing},
+		{"no result at all", `1. e4`, []chessnote.ParserOption{chessnote.WithLaxParsing()}, chessnote.OutcomeUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game, err := chessnote.ParseString(tt.pgn, tt.opts...)
+			if err != nil {
+				t.Fatalf("ParseString() failed: %v", err)
+			}
+			if got := game.Outcome(); got != tt.want {
+				t.Errorf("Outcome() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOutcomeString(t *testing.T) {
+	t.Parallel()
+	for _, s := range []string{"1-0", "0-1", "1/2-1/2", "*"} {
+		if got := chessnote.ParseOutcome(s).String(); got != s {
+			t.Errorf("ParseOutcome(%q).String() = %q", s, got)
+		}
+	}
+	if got := chessnote.OutcomeUnknown.String(); got != "" {
+		t.Errorf("OutcomeUnknown.String() = %q, want empty", got)
+	}
+}
+
+func TestGameResultConsistent(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		pgn  string
+		opts []chessnote.ParserOption
+		want bool
+	}{
+		{"agree", `[Result "1-0"] 1. e4 1-0`, nil, true},
+		{"padded tag agrees", `[Result " 1/2-1/2 "] 1. e4 1/2-1/2`, nil, true},
+		{"disagree", `[Result "1-0"] 1. e4 0-1`, nil, false},
+		{"unrecognized tag", `[Result "1/2"] 1. e4 1/2-1/2`, nil, false},
+		{"token only", `1. e4 0-1`, nil, true},
+		{"tag only", `[Result "0-1"] 1. e4`, []chessnote.ParserOption{chessnote.WithLaxParsing()}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game, err := chessnote.ParseString(tt.pgn, tt.opts...)
+			if err != nil {
+				t.Fatalf("ParseString() failed: %v", err)
+			}
+			if got := game.ResultConsistent(); got != tt.want {
+				t.Errorf("ResultConsistent() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseWithResultNormalization(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		pgn     string
+		prefer  chessnote.ResultSource
+		want    string
+		wantTag string
+	}{
+		{"keep both by default", `[Result "1-0"] 1. e4 0-1`, chessnote.ResultSourceNone, "0-1", "1-0"},
+		{"prefer movetext", `[Result "1-0"] 1. e4 0-1`, chessnote.ResultSourceMovetext, "0-1", "0-1"},
+		{"prefer tag", `[Result "1-0"] 1. e4 0-1`, chessnote.ResultSourceTag, "1-0", "1-0"},
+		{"padded tag is rewritten", `[Result " 1/2-1/2 "] 1. e4 1/2-1/2`, chessnote.ResultSourceMovetext, "1/2-1/2", "1/2-1/2"},
+		{"missing tag is added", `1. e4 *`, chessnote.ResultSourceMovetext, "*", "*"},
+		{"unrecognized preferred tag is ignored", `[Result "??"] 1. e4 1-0`, chessnote.ResultSourceTag, "1-0", "??"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game, err := chessnote.ParseString(tt.pgn, chessnote.WithResultNormalization(tt.prefer))
+			if err != nil {
+				t.Fatalf("ParseString() failed: %v", err)
+			}
+			if game.Result != tt.want {
+				t.Errorf("got Result %q, want %q", game.Result, tt.want)
+			}
+			if got := game.Tags["Result"]; got != tt.wantTag {
+				t.Errorf("got Result tag %q, want %q", got, tt.wantTag)
+			}
+		})
+	}
+}
+
+func TestGameNormalizeResult(t *testing.T) {
+	t.Parallel()
+	game, err := chessnote.ParseString(`[Result "1-0"] 1. e4 0-1`)
+	if err != nil {
+		t.Fatalf("ParseString() failed: %v", err)
+	}
+	if !game.NormalizeResult(chessnote.ResultSourceTag) {
+		t.Error("NormalizeResult() = false for a game it changed")
+	}
+	if !game.ResultConsistent() || game.Outcome() != chessnote.OutcomeWhiteWins {
+		t.Errorf("got Result %q and tag %q, want both 1-0", game.Result, game.Tags["Result"])
+	}
+	if game.NormalizeResult(chessnote.ResultSourceTag) {
+		t.Error("NormalizeResult() = true for an already consistent game")
+	}
+}
+
+func TestGameCanonicalizeResult(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		pgn        string
+		wantResult string
+		wantTag    string
+	}{
+		{"unicode draw in the tag", "[Result \"½-½\"]\n1. e4 e5 1/2-1/2", "1/2-1/2", "1/2-1/2"},
+		{"spaced white win", "[Result \"1 - 0\"]\n1. e4 e5 1-0", "1-0", "1-0"},
+		{"spaced black win", "[Result \" 0 - 1 \"]\n1. e4 e5 0-1", "0-1", "0-1"},
+		{"colon form", "[Result \"1:0\"]\n1. e4 e5 1-0", "1-0", "1-0"},
+		{"spaced fraction", "[Result \"1/2 - 1/2\"]\n1. e4 e5 1/2-1/2", "1/2-1/2", "1/2-1/2"},
+		{"word form", "[Result \"Draw\"]\n1. e4 e5 1/2-1/2", "1/2-1/2", "1/2-1/2"},
+		{"disagreement is kept", "[Result \"1 - 0\"]\n1. e4 e5 *", "*", "1-0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game, err := chessnote.ParseString(tt.pgn)
+			if err != nil {
+				t.Fatalf("ParseString() failed: %v", err)
+			}
+			if !game.CanonicalizeResult() {
+				t.Error("CanonicalizeResult() = false for a game it changed")
+			}
+			if game.Result != tt.wantResult || game.Tags["Result"] != tt.wantTag {
+				t.Errorf("got Result %q and tag %q, want %q and %q", game.Result, game.Tags["Result"], tt.wantResult, tt.wantTag)
+			}
+		})
+	}
+
+	game, err := chessnote.ParseString("[Result \"abandoned\"]\n1. e4 e5 *")
+	if err != nil {
+		t.Fatalf("ParseString() failed: %v", err)
+	}
+	if game.CanonicalizeResult() || game.Tags["Result"] != "abandoned" || game.Result != "*" {
+		t.Errorf("got Result %q and tag %q, want the game left unchanged", game.Result, game.Tags["Result"])
+	}
+}
+
+func TestParseUnicodeDrawResult(t *testing.T) {
+	t.Parallel()
+	game, err := chessnote.ParseString("[Result \"1/2-1/2\"]\n1. e4 e5 ½-½ {Agreed}")
+	if err != nil {
+		t.Fatalf("ParseString() failed: %v", err)
+	}
+	if game.Result != "1/2-1/2" || game.EndComment() != "Agreed" {
+		t.Errorf("got Result %q and end comment %q, want %q and %q", game.Result, game.EndComment(), "1/2-1/2", "Agreed")
+	}
+	if got := game.Outcome(); got != chessnote.OutcomeDraw {
+		t.Errorf("Outcome() = %v, want OutcomeDraw", got)
+	}
+	if !game.ResultConsistent() {
+		t.Error("ResultConsistent() = false, want true")
+	}
+	if errs := game.Validate(); errs != nil {
+		t.Errorf("Validate() = %v, want no problems", errs)
+	}
+	if got := game.ToPGN(); !strings.Contains(got, "1. e4 e5 1/2-1/2 {Agreed}") {
+		t.Errorf("ToPGN() = %q, want the draw written as 1/2-1/2", got)
+	}
+}
+
+func TestGameEndComments(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name             string
+		pgn              string
+		wantLastComments []string
+		wantEndComment   string
+		wantResult       string
+	}{
+		{
+			name:             "before the result",
+			pgn:              "1. e4 e5 2. Qh5 Nc6 3. Bc4 Nf6 4. Qxf7# {Scholar's mate} 1-0",
+			wantLastComments: []string{"Scholar's mate"},
+			wantResult:       "1-0",
+		},
+		{
+			name:           "after the result",
+			pgn:            "1. e4 e5 1/2-1/2 {Drawn by agreement}",
+			wantEndComment: "Drawn by agreement",
+			wantResult:     "1/2-1/2",
+		},
+		{
+			name:             "both placements",
+			pgn:              "1. d4 d5 {Resigns} 0-1 {A short game} {Source: archive}",
+			wantLastComments: []string{"Resigns"},
+			wantEndComment:   "A short game\nSource: archive",
+			wantResult:       "0-1",
+		},
+		{
+			name:             "lichess checkmate",
+			pgn:              "[Event \"Rated Blitz game\"]\n[Result \"1-0\"]\n\n1. e4 { [%clk 0:03:00] } 1... e5 { [%clk 0:03:00] } 2. Qh5 { [%clk 0:02:58] } 2... Nc6 { [%clk 0:02:57] } 3. Bc4 { [%clk 0:02:55] } 3... Nf6 { [%clk 0:02:50] } 4. Qxf7# { [%clk 0:02:53] } { White wins. } 1-0\n\n\n",
+			wantLastComments: []string{"[%clk 0:02:53]", "White wins."},
+			wantResult:       "1-0",
+		},
+		{
+			name:             "lichess resignation",
+			pgn:              "[Event \"Rated Rapid game\"]\n[Result \"1-0\"]\n\n1. d4 d5 2. c4 { Black resigns. } 1-0\n\n\n",
+			wantLastComments: []string{"Black resigns."},
+			wantResult:       "1-0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game, err := chessnote.ParseString(tt.pgn)
+			if err != nil {
+				t.Fatalf("ParseString() failed: %v", err)
+			}
+			last := game.Moves[len(game.Moves)-1]
+			if !reflect.DeepEqual(last.Comments, tt.wantLastComments) {
+				t.Errorf("got final move comments %q, want %q", last.Comments, tt.wantLastComments)
+			}
+			if game.Result != tt.wantResult {
+				t.Errorf("got Result %q, want %q", game.Result, tt.wantResult)
+			}
+			if got := game.EndComment(); got != tt.wantEndComment {
+				t.Errorf("EndComment() = %q, want %q", got, tt.wantEndComment)
+			}
+		})
+	}
+}
+
+func TestGameResultFromBoard(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		pgn    string
+		want   string
+		wantOK bool
+	}{
+		{"white mates", operaGame, "1-0", true},
+		{"black mates", "1. f3 e5 2. g4 Qh4# *", "0-1", true},
+		{"stalemate", "[FEN \"7k/8/8/6Q1/8/8/8/7K w - - 0 1\"]\n\n1. Qg6 *", "1/2-1/2", true},
+		{"insufficient material", "[FEN \"7k/8/8/8/8/8/6r1/5B1K w - - 0 1\"]\n\n1. Kxg2 1/2-1/2", "1/2-1/2", true},
+		{"recorded result is ignored", "1. f3 e5 2. g4 Qh4# 1-0", "0-1", true},
+		{"unfinished game", "1. e4 e5 1/2-1/2", "", false},
+		{"unreplayable game", "1. e4 e5 2. Qxf7# 1-0", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game, err := chessnote.ParseString(tt.pgn)
+			if err != nil {
+				t.Fatalf("ParseString() failed: %v", err)
+			}
+			got, ok := game.ResultFromBoard()
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("ResultFromBoard() = %q, %t, want %q, %t", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
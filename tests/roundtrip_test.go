@@ -0,0 +1,174 @@
+package chessnote_test
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/YashBhalodi/chessnote"
+)
+
+func TestRoundTripKasparovGames(t *testing.T) {
+	t.Parallel()
+	data, err := os.ReadFile("../examples/multiple-game-pgn/Kasparov.pgn")
+	if err != nil {
+		t.Fatalf("failed to read PGN file: %v", err)
+	}
+	for i, pgn := range chessnote.SplitMultiGame(string(data)) {
+		game, err := chessnote.ParseString(pgn)
+		if err != nil {
+			t.Fatalf("game %d: ParseString() failed: %v", i+1, err)
+		}
+		parsed, err := chessnote.RoundTrip(game)
+		if err != nil {
+			t.Fatalf("game %d: RoundTrip() error = %v", i+1, err)
+		}
+		if diffs := chessnote.DiffGames(game, parsed); diffs != nil {
+			t.Errorf("game %d: round trip differs: %v", i+1, diffs)
+		}
+	}
+}
+
+func TestRoundTripAnnotatedGame(t *testing.T) {
+	t.Parallel()
+	pgn := `[Event "Annotated"]
+[Result "*"]
+
+{Intro} 1. e4 $1 {Best by test} e5 (1... c5 {Sicilian} 2. Nf3 (2. c3 d5) d6)
+({Or} 1... e6) 2. Nf3 Nc6 {Multi
+line} * {Unfinished}`
+	game, err := chessnote.ParseString(pgn)
+	if err != nil {
+		t.Fatalf("ParseString() failed: %v", err)
+	}
+	parsed, err := chessnote.RoundTrip(game)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v\n%s", err, game.ToPGN())
+	}
+	if !game.Equal(parsed) {
+		t.Errorf("round trip differs: %v", chessnote.DiffGames(game, parsed))
+	}
+}
+
+func TestRoundTripEscapedTagValues(t *testing.T) {
+	t.Parallel()
+	pgn := "[Event 'The \"Immortal\" Game']\n[Site 'C:\\games']\n\n1. e4 e5 *"
+	game, err := chessnote.ParseString(pgn, chessnote.WithLenientTags())
+	if err != nil {
+		t.Fatalf("ParseString() failed: %v", err)
+	}
+	encoded := game.ToPGN()
+	for _, want := range []string{`[Event "The \"Immortal\" Game"]`, `[Site "C:\\games"]`} {
+		if !strings.Contains(encoded, want) {
+			t.Errorf("ToPGN() =\n%s\nwant it to contain %s", encoded, want)
+		}
+	}
+	parsed, err := chessnote.RoundTrip(game)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v\n%s", err, encoded)
+	}
+	if !game.Equal(parsed) {
+		t.Errorf("round trip differs: %v", chessnote.DiffGames(game, parsed))
+	}
+}
+
+func TestRoundTripLongAlgebraic(t *testing.T) {
+	t.Parallel()
+	game, err := chessnote.ParseString("1. e2-e4 d7-d5 2. e4xd5 Qd8xd5 3. Ng1-f3 *")
+	if err != nil {
+		t.Fatalf("ParseString() failed: %v", err)
+	}
+	parsed, err := chessnote.RoundTrip(game)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v\n%s", err, game.ToPGN())
+	}
+	if !game.Equal(parsed) {
+		t.Errorf("round trip differs: %v", chessnote.DiffGames(game, parsed))
+	}
+	if got, want := parsed.ToPGN(), "1. e4 d5 2. exd5 Qd8xd5 3. Ng1f3 *"; !strings.Contains(got, want) {
+		t.Errorf("ToPGN() = %q, want it to contain %q", got, want)
+	}
+}
+
+func TestRoundTripVariationMoveNumbers(t *testing.T) {
+	t.Parallel()
+	pgn := `[FEN "4k3/pppp4/8/8/8/8/PPPP4/4K3 w - - 0 14"]
+
+14. a3 a6 (14... b6 15. b3 (15. b4 c6) c6) 15. c3 (15. c4 d6) d6 *`
+	game, err := chessnote.ParseString(pgn)
+	if err != nil {
+		t.Fatalf("ParseString() failed: %v", err)
+	}
+	encoded := game.ToPGN()
+	want := "14. a3 a6 (14... b6 15. b3 (15. b4 c6) 15... c6) 15. c3 (15. c4 d6) 15... d6 *"
+	if !strings.Contains(encoded, want) {
+		t.Errorf("ToPGN() =\n%s\nwant movetext:\n%s", encoded, want)
+	}
+	parsed, err := chessnote.RoundTrip(game)
+	if err != nil {
+		t.Fatalf("RoundTrip() error = %v\n%s", err, encoded)
+	}
+	if len(parsed.ParseWarnings) > 0 {
+		t.Errorf("re-parsed game has move number warnings: %q", parsed.ParseWarnings)
+	}
+	if !game.Equal(parsed) {
+		t.Errorf("round trip differs: %v", chessnote.DiffGames(game, parsed))
+	}
+}
+
+func TestDiffGames(t *testing.T) {
+	t.Parallel()
+	original := "[Event \"E\"]\n[Result \"*\"]\n\n1. e4 e5 (1... c5) 2. Nf3 {Develops} *"
+	tests := []struct {
+		name   string
+		modify func(g *chessnote.Game)
+		want   []string
+	}{
+		{"equal", func(g *chessnote.Game) {}, nil},
+		{
+			name:   "changed tag and new tag",
+			modify: func(g *chessnote.Game) { g.Tags["Event"] = "F"; g.Tags["Site"] = "S" },
+			want:   []string{`tag Event: "E" != "F"`, `tag Site: missing != "S"`},
+		},
+		{
+			name:   "changed move",
+			modify: func(g *chessnote.Game) { g.Moves[2].To = chessnote.Square{File: 2, Rank: 2} },
+			want:   []string{`Moves[2]: "Nf3" != "Nc3"`},
+		},
+		{
+			name:   "removed comment and NAG added",
+			modify: func(g *chessnote.Game) { g.Moves[2].Comments = nil; g.Moves[0].NAGs = []int{1} },
+			want:   []string{`Moves[0].NAGs: [] != [1]`, `Moves[2].Comments: ["Develops"] != []`},
+		},
+		{
+			name:   "truncated variation",
+			modify: func(g *chessnote.Game) { g.Moves[1].Variations[0] = nil },
+			want:   []string{`Moves[1].Variations[0]: 1 moves != 0 moves`},
+		},
+		{
+			name:   "different result",
+			modify: func(g *chessnote.Game) { g.Result = "1-0" },
+			want:   []string{`result: "*" != "1-0"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := chessnote.ParseString(original)
+			if err != nil {
+				t.Fatalf("ParseString() failed: %v", err)
+			}
+			b, err := chessnote.ParseString(original)
+			if err != nil {
+				t.Fatalf("ParseString() failed: %v", err)
+			}
+			tt.modify(b)
+			if got := chessnote.DiffGames(a, b); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DiffGames() = %q, want %q", got, tt.want)
+			}
+			if got := a.Equal(b); got != (tt.want == nil) {
+				t.Errorf("Equal() = %t, want %t", got, tt.want == nil)
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package chessnote_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/YashBhalodi/chessnote"
+)
+
+func TestMoveString(t *testing.T) {
+	t.Parallel()
+	tests := []string{"e4", "Nf3", "Nbd2", "N1c3", "Qh4e1", "exd5", "Rxf8+", "exd8=Q+", "e8=N", "O-O", "O-O-O#"}
+	for _, san := range tests {
+		t.Run(san, func(t *testing.T) {
+			game, err := chessnote.ParseString("1. " + san + " *")
+			if err != nil {
+				t.Fatalf("ParseString() failed: %v", err)
+			}
+			if got := game.Moves[0].String(); got != san {
+				t.Errorf("String() = %q, want %q", got, san)
+			}
+		})
+	}
+}
+
+func TestBoardSAN(t *testing.T) {
+	t.Parallel()
+	d2 := chessnote.Square{File: 3, Rank: 1}
+	tests := []struct {
+		name string
+		fen  string
+		move chessnote.Move
+		want string
+	}{
+		{
+			name: "one knight can reach the square",
+			fen:  "rnbqkbnr/ppp1pppp/8/3p4/3P4/8/PPP1PPPP/RNBQKBNR w KQkq d6 0 2",
+			move: chessnote.Move{Piece: chessnote.Knight, To: d2},
+			want: "Nd2",
+		},
+		{
+			name: "two knights can reach the square",
+			fen:  "rnbqkb1r/ppp1pppp/5n2/3p4/3P4/5N2/PPP1PPPP/RNBQKB1R w KQkq - 2 3",
+			move: chessnote.Move{Piece: chessnote.Knight, From: chessnote.Square{File: 1}, HasFromFile: true, To: d2},
+			want: "Nbd2",
+		},
+		{
+			name: "other knight is pinned",
+			fen:  "rnbqk1nr/ppp2ppp/3p4/4p3/1b2P3/2NP4/PPP2PPP/R1BQKBNR w KQkq - 0 4",
+			move: chessnote.Move{Piece: chessnote.Knight, From: chessnote.Square{File: 6}, HasFromFile: true, To: chessnote.Square{File: 4, Rank: 1}},
+			want: "Ne2",
+		},
+		{
+			name: "knights share a file",
+			fen:  "4k3/8/8/1N6/8/8/8/1N2K3 w - - 0 1",
+			move: chessnote.Move{Piece: chessnote.Knight, From: chessnote.Square{File: 1}, HasFromFile: true, HasFromRank: true, To: chessnote.Square{File: 2, Rank: 2}},
+			want: "N1c3",
+		},
+		{
+			name: "queens share a file and a rank",
+			fen:  "4k3/8/8/8/8/Q7/8/Q1Q1K3 w - - 0 1",
+			move: chessnote.Move{Piece: chessnote.Queen, From: chessnote.Square{File: 0}, HasFromFile: true, HasFromRank: true, To: chessnote.Square{File: 2, Rank: 2}},
+			want: "Qa1c3",
+		},
+		{
+			name: "pawn capture",
+			fen:  "rnbqkbnr/ppp1pppp/8/3p4/4P3/8/PPPP1PPP/RNBQKBNR w KQkq d6 0 2",
+			move: chessnote.Move{Piece: chessnote.Pawn, To: chessnote.Square{File: 3, Rank: 4}},
+			want: "exd5",
+		},
+		{
+			name: "promotion with check",
+			fen:  "3rk3/4P3/8/8/8/8/8/4K3 w - - 0 1",
+			move: chessnote.Move{Piece: chessnote.Pawn, To: chessnote.Square{File: 3, Rank: 7}, Promotion: chessnote.Queen},
+			want: "exd8=Q+",
+		},
+		{
+			name: "checkmate",
+			fen:  "rnbqkbnr/pppp1ppp/8/4p3/6P1/5P2/PPPPP2P/RNBQKBNR b KQkq g3 0 2",
+			move: chessnote.Move{Piece: chessnote.Queen, To: chessnote.Square{File: 7, Rank: 3}},
+			want: "Qh4#",
+		},
+		{
+			name: "castling",
+			fen:  "4k3/8/8/8/8/8/8/4K2R w K - 0 1",
+			move: chessnote.Move{IsKingsideCastle: true},
+			want: "O-O",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := chessnote.ParseFEN(tt.fen)
+			if err != nil {
+				t.Fatalf("ParseFEN() failed: %v", err)
+			}
+			before := b.FEN()
+			got, err := b.SAN(tt.move)
+			if err != nil {
+				t.Fatalf("SAN() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("SAN() = %q, want %q", got, tt.want)
+			}
+			if b.FEN() != before {
+				t.Errorf("SAN() modified the board")
+			}
+		})
+	}
+}
+
+func TestBoardSANIllegalMove(t *testing.T) {
+	t.Parallel()
+	b := chessnote.NewBoard()
+	if _, err := b.SAN(chessnote.Move{Piece: chessnote.Knight, To: chessnote.Square{File: 4, Rank: 3}}); err == nil {
+		t.Errorf("SAN() expected an error for an unreachable square, but got nil")
+	}
+}
+
+func TestGameMovesInSAN(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		pgn  string
+		want []string
+	}{
+		{
+			name: "known game",
+			pgn:  operaGame,
+			want: []string{
+				"e4", "e5", "Nf3", "d6", "d4", "Bg4", "dxe5", "Bxf3", "Qxf3", "dxe5",
+				"Bc4", "Nf6", "Qb3", "Qe7", "Nc3", "c6", "Bg5", "b5", "Nxb5", "cxb5",
+				"Bxb5+", "Nbd7", "O-O-O", "Rd8", "Rxd7", "Rxd7", "Rd1", "Qe6", "Bxd7+", "Nxd7",
+				"Qb8+", "Nxb8", "Rd8#",
+			},
+		},
+		{
+			name: "notation derived from the position",
+			pgn:  "1. e4 d5 2. exd5 Qxd5 3. Ngf3 Qe4 4. Be2 Qe2 *",
+			want: []string{"e4", "d5", "exd5", "Qxd5", "Nf3", "Qe4+", "Be2", "Qxe2+"},
+		},
+		{
+			name: "written notation after an illegal move",
+			pgn:  "1. e4 e5 2. Ke3 Ngf6 *",
+			want: []string{"e4", "e5", "Ke3", "Ngf6"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game, err := chessnote.ParseString(tt.pgn)
+			if err != nil {
+				t.Fatalf("ParseString() failed: %v", err)
+			}
+			if got := game.MovesInSAN(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MovesInSAN() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoveCoordinates(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		san      string
+		wantFrom string
+		wantTo   string
+	}{
+		{"Nf3", "??", "f3"},
+		{"e4", "??", "e4"},
+		{"exd5", "e?", "d5"},
+		{"Nbd2", "b?", "d2"},
+		{"N1c3", "?1", "c3"},
+		{"Qh4e1", "h4", "e1"},
+		{"Ng1-f3", "g1", "f3"},
+		{"O-O", "??", "??"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.san, func(t *testing.T) {
+			game, err := chessnote.ParseString("1. " + tt.san + " *")
+			if err != nil {
+				t.Fatalf("ParseString() failed: %v", err)
+			}
+			from, to := game.Moves[0].Coordinates()
+			if from != tt.wantFrom || to != tt.wantTo {
+				t.Errorf("Coordinates() = %q, %q, want %q, %q", from, to, tt.wantFrom, tt.wantTo)
+			}
+		})
+	}
+
+	b := chessnote.NewBoard()
+	m, err := b.ParseUCI("g1f3")
+	if err != nil {
+		t.Fatalf("ParseUCI() failed: %v", err)
+	}
+	if from, to := m.Coordinates(); from != "g1" || to != "f3" {
+		t.Errorf("Coordinates() of a UCI move = %q, %q, want %q, %q", from, to, "g1", "f3")
+	}
+}
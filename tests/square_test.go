@@ -0,0 +1,75 @@
+package chessnote_test
+
+import (
+	"testing"
+
+	"github.com/YashBhalodi/chessnote"
+)
+
+func TestSquareConversions(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		sq        chessnote.Square
+		algebraic string
+		index     int
+		x88       int
+	}{
+		{chessnote.Square{File: 0, Rank: 0}, "a1", 0, 0x00},
+		{chessnote.Square{File: 7, Rank: 0}, "h1", 7, 0x07},
+		{chessnote.Square{File: 0, Rank: 7}, "a8", 56, 0x70},
+		{chessnote.Square{File: 7, Rank: 7}, "h8", 63, 0x77},
+		{chessnote.Square{File: 4, Rank: 3}, "e4", 28, 0x34},
+	}
+	for _, tt := range tests {
+		t.Run(tt.algebraic, func(t *testing.T) {
+			if got := tt.sq.ToAlgebraic(); got != tt.algebraic {
+				t.Errorf("ToAlgebraic() = %q, want %q", got, tt.algebraic)
+			}
+			if got := tt.sq.Index(); got != tt.index {
+				t.Errorf("Index() = %d, want %d", got, tt.index)
+			}
+			if got := tt.sq.To0x88(); got != tt.x88 {
+				t.Errorf("To0x88() = %#x, want %#x", got, tt.x88)
+			}
+
+			if got, err := chessnote.SquareFromAlgebraic(tt.algebraic); err != nil || got != tt.sq {
+				t.Errorf("SquareFromAlgebraic(%q) = %+v, %v, want %+v", tt.algebraic, got, err, tt.sq)
+			}
+			if got, err := chessnote.SquareFromIndex(tt.index); err != nil || got != tt.sq {
+				t.Errorf("SquareFromIndex(%d) = %+v, %v, want %+v", tt.index, got, err, tt.sq)
+			}
+			if got, err := chessnote.SquareFrom0x88(tt.x88); err != nil || got != tt.sq {
+				t.Errorf("SquareFrom0x88(%#x) = %+v, %v, want %+v", tt.x88, got, err, tt.sq)
+			}
+		})
+	}
+}
+
+func TestSquareConversionErrors(t *testing.T) {
+	t.Parallel()
+	offBoard := chessnote.Square{File: 8, Rank: 0}
+	if got := offBoard.ToAlgebraic(); got != "" {
+		t.Errorf("ToAlgebraic() = %q for an off-board square, want \"\"", got)
+	}
+	if got := offBoard.Index(); got != -1 {
+		t.Errorf("Index() = %d for an off-board square, want -1", got)
+	}
+	if got := offBoard.To0x88(); got != -1 {
+		t.Errorf("To0x88() = %d for an off-board square, want -1", got)
+	}
+	for _, s := range []string{"", "e", "i1", "a9", "e44"} {
+		if _, err := chessnote.SquareFromAlgebraic(s); err == nil {
+			t.Errorf("SquareFromAlgebraic(%q) expected an error, but got nil", s)
+		}
+	}
+	for _, i := range []int{-1, 64} {
+		if _, err := chessnote.SquareFromIndex(i); err == nil {
+			t.Errorf("SquareFromIndex(%d) expected an error, but got nil", i)
+		}
+	}
+	for _, i := range []int{-1, 0x08, 0x78, 0x80} {
+		if _, err := chessnote.SquareFrom0x88(i); err == nil {
+			t.Errorf("SquareFrom0x88(%#x) expected an error, but got nil", i)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package chessnote_test
+
+import (
+	"testing"
+
+	"github.com/YashBhalodi/chessnote"
+)
+
+func TestGameSummary(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		pgn  string
+		want string
+	}{
+		{
+			name: "full tags",
+			pgn:  fideTagsGame,
+			want: "White:     Carlsen, Magnus (2830)\n" +
+				"Black:     Nakamura, Hikaru\n" +
+				"Event:     Candidates\n" +
+				"Site:      Toronto\n" +
+				"Date:      2024.04.04\n" +
+				"Result:    1/2-1/2\n" +
+				"Plies:     2\n" +
+				"Annotated: no\n",
+		},
+		{
+			name: "annotated game without tags",
+			pgn:  "1. e4 e5 2. Nf3 (2. f4 exf4) Nc6 *",
+			want: "White:     ?\n" +
+				"Black:     ?\n" +
+				"Event:     ?\n" +
+				"Site:      ?\n" +
+				"Date:      ?\n" +
+				"Result:    *\n" +
+				"Plies:     4\n" +
+				"Annotated: yes\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game, err := chessnote.ParseString(tt.pgn)
+			if err != nil {
+				t.Fatalf("ParseString() failed: %v", err)
+			}
+			if got := game.Summary(); got != tt.want {
+				t.Errorf("Summary() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
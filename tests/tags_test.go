@@ -0,0 +1,146 @@
+package chessnote_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/YashBhalodi/chessnote"
+)
+
+const fideTagsGame = `[EventDate "2024.04.03"]
+[Annotation "none"]
+[BlackTitle "GM"]
+[Result "1/2-1/2"]
+[WhiteFideId "1503014"]
+[Black "Nakamura, Hikaru"]
+[White "Carlsen, Magnus"]
+[Round "1"]
+[WhiteTitle "GM"]
+[Date "2024.04.04"]
+[BlackFideId "2016192"]
+[Site "Toronto"]
+[Custom "x"]
+[Annotator "Kasparov, Garry"]
+[Event "Candidates"]
+[Termination "normal"]
+[WhiteElo "2830"]
+
+1. e4 e5 1/2-1/2`
+
+func TestGameFIDETagAccessors(t *testing.T) {
+	t.Parallel()
+	game, err := chessnote.ParseString(fideTagsGame)
+	if err != nil {
+		t.Fatalf("ParseString() failed: %v", err)
+	}
+	for _, tt := range []struct {
+		name, got, want string
+	}{
+		{"WhiteTitle", game.WhiteTitle(), "GM"},
+		{"BlackTitle", game.BlackTitle(), "GM"},
+		{"WhiteFideID", game.WhiteFideID(), "1503014"},
+		{"BlackFideID", game.BlackFideID(), "2016192"},
+		{"EventDate", game.EventDate(), "2024.04.03"},
+		{"Annotator", game.Annotator(), "Kasparov, Garry"},
+	} {
+		if tt.got != tt.want {
+			t.Errorf("%s() = %q, want %q", tt.name, tt.got, tt.want)
+		}
+	}
+
+	empty, err := chessnote.ParseString("1. e4 *")
+	if err != nil {
+		t.Fatalf("ParseString() failed: %v", err)
+	}
+	if got := empty.WhiteTitle(); got != "" {
+		t.Errorf("WhiteTitle() = %q for a game without the tag, want \"\"", got)
+	}
+	if got := empty.Annotator(); got != "" {
+		t.Errorf("Annotator() = %q for a game without the tag, want \"\"", got)
+	}
+}
+
+func TestGameTagKeys(t *testing.T) {
+	t.Parallel()
+	game, err := chessnote.ParseString(fideTagsGame)
+	if err != nil {
+		t.Fatalf("ParseString() failed: %v", err)
+	}
+	want := []string{
+		"Event", "Site", "Date", "Round", "White", "Black", "Result",
+		"WhiteTitle", "BlackTitle", "WhiteElo", "WhiteFideId", "BlackFideId", "EventDate",
+		"Termination", "Annotator", "Annotation", "Custom",
+	}
+	if got := game.TagKeys(); !reflect.DeepEqual(got, want) {
+		t.Errorf("TagKeys() = %v, want %v", got, want)
+	}
+}
+
+func TestGameDeclaredPlyCount(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		tags   map[string]string
+		want   int
+		wantOK bool
+	}{
+		{"present", map[string]string{"PlyCount": "41"}, 41, true},
+		{"zero", map[string]string{"PlyCount": "0"}, 0, true},
+		{"missing", map[string]string{}, 0, false},
+		{"not a number", map[string]string{"PlyCount": "forty"}, 0, false},
+		{"negative", map[string]string{"PlyCount": "-1"}, 0, false},
+	}
+	for _, tt := range tests {
+		game := &chessnote.Game{Tags: tt.tags}
+		got, ok := game.DeclaredPlyCount()
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("%s: DeclaredPlyCount() = %d, %v, want %d, %v", tt.name, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestGameRatings(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		white, black string
+		wantAverage  int
+		wantOK       bool
+		wantCategory string
+	}{
+		{"both rated", "2830", "2789", 2809, true, "2800-2899"},
+		{"band boundary", "2200", "2201", 2200, true, "2200-2299"},
+		{"rounded down", "1501", "1498", 1499, true, "1400-1499"},
+		{"white missing", "", "2100", 0, false, ""},
+		{"black unrated", "2100", "-", 0, false, ""},
+		{"zero rating", "0", "2100", 0, false, ""},
+	}
+	for _, tt := range tests {
+		tags := map[string]string{}
+		if tt.white != "" {
+			tags["WhiteElo"] = tt.white
+		}
+		if tt.black != "" {
+			tags["BlackElo"] = tt.black
+		}
+		game := &chessnote.Game{Tags: tags}
+		avg, ok := game.AverageElo()
+		if avg != tt.wantAverage || ok != tt.wantOK {
+			t.Errorf("%s: AverageElo() = %d, %v, want %d, %v", tt.name, avg, ok, tt.wantAverage, tt.wantOK)
+		}
+		if got := game.RatingCategory(); got != tt.wantCategory {
+			t.Errorf("%s: RatingCategory() = %q, want %q", tt.name, got, tt.wantCategory)
+		}
+	}
+
+	game, err := chessnote.ParseString(fideTagsGame)
+	if err != nil {
+		t.Fatalf("ParseString() failed: %v", err)
+	}
+	if elo, ok := game.WhiteElo(); elo != 2830 || !ok {
+		t.Errorf("WhiteElo() = %d, %v, want 2830, true", elo, ok)
+	}
+	if elo, ok := game.BlackElo(); elo != 0 || ok {
+		t.Errorf("BlackElo() = %d, %v, want 0, false for a game without the tag", elo, ok)
+	}
+}
@@ -0,0 +1,57 @@
+package chessnote_test
+
+import (
+	"testing"
+
+	"github.com/YashBhalodi/chessnote"
+)
+
+func TestParseTermination(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		s    string
+		want chessnote.Termination
+	}{
+		{"Normal", chessnote.TerminationNormal},
+		{"Time forfeit", chessnote.TerminationTimeForfeit},
+		{"abandoned", chessnote.TerminationAbandoned},
+		{" Rules infraction ", chessnote.TerminationRulesInfraction},
+		{"UNTERMINATED", chessnote.TerminationUnterminated},
+		{"Insufficient material", chessnote.Termination("insufficient material")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			if got := chessnote.ParseTermination(tt.s); got != tt.want {
+				t.Errorf("ParseTermination(%q) = %q, want %q", tt.s, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGameTermination(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		pgn  string
+		want chessnote.Termination
+	}{
+		{"tag", `[Termination "Time forfeit"] 1. e4 1-0`, chessnote.TerminationTimeForfeit},
+		{"tag wins over comment", `[Termination "Normal"] 1. e4 1-0 {Black resigns}`, chessnote.TerminationNormal},
+		{"comment after result", `1. e4 1-0 {White wins by resignation}`, chessnote.TerminationResignation},
+		{"comment on the final move", `1. e4 {Black lost on time} 1-0`, chessnote.TerminationTimeForfeit},
+		{"draw agreed", `1. e4 e5 1/2-1/2 {Draw agreed}`, chessnote.TerminationAgreement},
+		{"unrecognized comment", `1. e4 1-0 {Well played}`, ""},
+		{"no information", `1. e4 *`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game, err := chessnote.ParseString(tt.pgn)
+			if err != nil {
+				t.Fatalf("ParseString() failed: %v", err)
+			}
+			if got := game.Termination(); got != tt.want {
+				t.Errorf("Termination() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
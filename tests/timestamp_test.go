@@ -0,0 +1,58 @@
+package chessnote_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/YashBhalodi/chessnote"
+)
+
+func TestParseMoveTimestamps(t *testing.T) {
+	t.Parallel()
+	pgn := `1. d4 {[%ts 2023-05-14T18:30:00Z] Sent early} Nf6 {2023.05.16} 2. c4 {2023-05-17 09:15}
+e6 {[%emt 0:00:05] Thinking} 3. Nc3 {Played 2023.05.20} (3. Nf3 {2023.05.21}) *`
+	game, err := chessnote.ParseString(pgn)
+	if err != nil {
+		t.Fatalf("ParseString() failed: %v", err)
+	}
+
+	want := []time.Time{
+		time.Date(2023, 5, 14, 18, 30, 0, 0, time.UTC),
+		time.Date(2023, 5, 16, 0, 0, 0, 0, time.UTC),
+		time.Date(2023, 5, 17, 9, 15, 0, 0, time.UTC),
+		{},
+		{},
+	}
+	for i, m := range game.Moves {
+		if !m.Timestamp.Equal(want[i]) {
+			t.Errorf("ply %d: got Timestamp %v, want %v", i+1, m.Timestamp, want[i])
+		}
+	}
+	if got, want := game.Moves[4].Variations[0][0].Timestamp, time.Date(2023, 5, 21, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("variation: got Timestamp %v, want %v", got, want)
+	}
+
+	// The comments themselves are preserved.
+	if want := []string{"[%ts 2023-05-14T18:30:00Z] Sent early"}; !reflect.DeepEqual(game.Moves[0].Comments, want) {
+		t.Errorf("got comments %q, want %q", game.Moves[0].Comments, want)
+	}
+	if want := []string{"[%emt 0:00:05] Thinking"}; !reflect.DeepEqual(game.Moves[3].Comments, want) {
+		t.Errorf("got comments %q, want %q", game.Moves[3].Comments, want)
+	}
+}
+
+func TestParseMoveTimestampsWithoutComments(t *testing.T) {
+	t.Parallel()
+	game, err := chessnote.ParseString(`1. e4 {[%ts 2024.01.02 03:04:05]} *`, chessnote.WithSkipComments())
+	if err != nil {
+		t.Fatalf("ParseString() failed: %v", err)
+	}
+	want := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	if got := game.Moves[0].Timestamp; !got.Equal(want) {
+		t.Errorf("got Timestamp %v, want %v", got, want)
+	}
+	if game.Moves[0].Comments != nil {
+		t.Errorf("expected comments to be skipped, got %q", game.Moves[0].Comments)
+	}
+}
@@ -0,0 +1,58 @@
+package chessnote_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/YashBhalodi/chessnote"
+)
+
+func TestTokenizer(t *testing.T) {
+	t.Parallel()
+	input := "[Event \"Test\"]\n\n1. e4  {Best}\te5 *"
+	tokenize := func(preserve bool) []chessnote.Token {
+		tz := chessnote.NewTokenizer(strings.NewReader(input))
+		tz.SetPreserveWhitespace(preserve)
+		var tokens []chessnote.Token
+		for {
+			tok := tz.Next()
+			tokens = append(tokens, tok)
+			if tok.Kind == chessnote.TokenEOF {
+				return tokens
+			}
+		}
+	}
+
+	t.Run("whitespace skipped", func(t *testing.T) {
+		want := []chessnote.Token{
+			{Kind: chessnote.TokenLBracket, Literal: "[", Line: 1, Column: 1},
+			{Kind: chessnote.TokenIdent, Literal: "Event", Line: 1, Column: 2},
+			{Kind: chessnote.TokenString, Literal: "Test", Line: 1, Column: 8},
+			{Kind: chessnote.TokenRBracket, Literal: "]", Line: 1, Column: 14},
+			{Kind: chessnote.TokenNumber, Literal: "1", Line: 3, Column: 1},
+			{Kind: chessnote.TokenDot, Literal: ".", Line: 3, Column: 2},
+			{Kind: chessnote.TokenIdent, Literal: "e4", Line: 3, Column: 4},
+			{Kind: chessnote.TokenComment, Literal: "Best", Line: 3, Column: 8},
+			{Kind: chessnote.TokenIdent, Literal: "e5", Line: 3, Column: 15},
+			{Kind: chessnote.TokenAsterisk, Literal: "*", Line: 3, Column: 18},
+			{Kind: chessnote.TokenEOF, Line: 3, Column: 19},
+		}
+		if got := tokenize(false); !reflect.DeepEqual(got, want) {
+			t.Errorf("got tokens\n%+v\nwant\n%+v", got, want)
+		}
+	})
+
+	t.Run("whitespace preserved", func(t *testing.T) {
+		var spaces []string
+		for _, tok := range tokenize(true) {
+			if tok.Kind == chessnote.TokenWhitespace {
+				spaces = append(spaces, tok.Literal)
+			}
+		}
+		want := []string{" ", "\n\n", " ", "  ", "\t", " "}
+		if !reflect.DeepEqual(spaces, want) {
+			t.Errorf("got whitespace %q, want %q", spaces, want)
+		}
+	})
+}
@@ -0,0 +1,116 @@
+package chessnote_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/YashBhalodi/chessnote"
+)
+
+func TestGameFindMoves(t *testing.T) {
+	t.Parallel()
+	pgn := `1. e4 e5 2. Nf3 Nc6 (2... d6 3. d4 exd4 (3... Nd7 4. dxe5+) 4. Nxd4) 3. Bb5 a6 4. Bxc6 dxc6 5. O-O *`
+	game, err := chessnote.ParseString(pgn)
+	if err != nil {
+		t.Fatalf("ParseString() failed: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		pred      func(chessnote.Move) bool
+		wantPaths [][]int
+		wantPlies []int
+	}{
+		{
+			name:      "all captures",
+			pred:      func(m chessnote.Move) bool { return m.IsCapture },
+			wantPaths: [][]int{{3, 0, 2}, {3, 0, 2, 0, 1}, {3, 0, 3}, {6}, {7}},
+			wantPlies: []int{6, 7, 7, 7, 8},
+		},
+		{
+			name:      "checks that are captures",
+			pred:      func(m chessnote.Move) bool { return m.IsCheck && m.IsCapture },
+			wantPaths: [][]int{{3, 0, 2, 0, 1}},
+			wantPlies: []int{7},
+		},
+		{
+			name:      "castling",
+			pred:      func(m chessnote.Move) bool { return m.IsKingsideCastle },
+			wantPaths: [][]int{{8}},
+			wantPlies: []int{9},
+		},
+		{
+			name: "promotions",
+			pred: func(m chessnote.Move) bool { return m.Promotion != chessnote.Pawn },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			refs := game.FindMoves(tt.pred)
+			if len(refs) != len(tt.wantPaths) {
+				t.Fatalf("FindMoves() returned %d refs, want %d", len(refs), len(tt.wantPaths))
+			}
+			for i, ref := range refs {
+				if !reflect.DeepEqual(ref.Path, tt.wantPaths[i]) {
+					t.Errorf("ref %d: got path %v, want %v", i, ref.Path, tt.wantPaths[i])
+				}
+				if ref.Ply != tt.wantPlies[i] {
+					t.Errorf("ref %d: got ply %d, want %d", i, ref.Ply, tt.wantPlies[i])
+				}
+				if !tt.pred(ref.Move) {
+					t.Errorf("ref %d: referenced move does not match the predicate", i)
+				}
+			}
+		})
+	}
+}
+
+func TestGameMoveAt(t *testing.T) {
+	t.Parallel()
+	pgn := `1. e4 e5 2. Nf3 Nc6 (2... d6 3. d4 exd4 (3... Nd7 4. dxe5+) 4. Nxd4) 3. Bb5 *`
+	game, err := chessnote.ParseString(pgn)
+	if err != nil {
+		t.Fatalf("ParseString() failed: %v", err)
+	}
+
+	tests := []struct {
+		path   []int
+		want   string
+		wantOK bool
+	}{
+		{path: []int{0}, want: "e4", wantOK: true},
+		{path: []int{4}, want: "Bb5", wantOK: true},
+		{path: []int{3, 0, 0}, want: "d6", wantOK: true},
+		{path: []int{3, 0, 2, 0, 1}, want: "dxe5+", wantOK: true},
+		{path: nil},
+		{path: []int{5}},
+		{path: []int{-1}},
+		{path: []int{3, 0}},
+		{path: []int{3, 1, 0}},
+		{path: []int{0, 0, 0}},
+		{path: []int{3, 0, 4}},
+	}
+	for _, tt := range tests {
+		m, ok := game.MoveAt(tt.path)
+		if ok != tt.wantOK {
+			t.Errorf("MoveAt(%v) ok = %v, want %v", tt.path, ok, tt.wantOK)
+			continue
+		}
+		if ok && m.String() != tt.want {
+			t.Errorf("MoveAt(%v) = %s, want %s", tt.path, m, tt.want)
+		}
+	}
+
+	for _, ref := range game.FindMoves(func(chessnote.Move) bool { return true }) {
+		m, ok := game.MoveAt(ref.Path)
+		if !ok || !reflect.DeepEqual(*m, ref.Move) {
+			t.Errorf("MoveAt(%v) = %+v, %v, want %+v", ref.Path, m, ok, ref.Move)
+		}
+	}
+
+	m, _ := game.MoveAt([]int{3, 0, 1})
+	m.Comments = append(m.Comments, "Main move")
+	if got := game.Moves[3].Variations[0][1].Comments; !reflect.DeepEqual(got, []string{"Main move"}) {
+		t.Errorf("editing through MoveAt: got comments %v", got)
+	}
+}
@@ -0,0 +1,178 @@
+package chessnote_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/YashBhalodi/chessnote"
+)
+
+func TestGameValidate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid game", func(t *testing.T) {
+		game, err := chessnote.ParseString(operaGame)
+		if err != nil {
+			t.Fatalf("ParseString() failed: %v", err)
+		}
+		if errs := game.Validate(); errs != nil {
+			t.Errorf("Validate() = %v, want no problems", errs)
+		}
+	})
+
+	t.Run("game starting from FEN with black to move", func(t *testing.T) {
+		pgn := `[FEN "rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1"]
+
+1... e5 2. Nf3 *`
+		game, err := chessnote.ParseString(pgn)
+		if err != nil {
+			t.Fatalf("ParseString() failed: %v", err)
+		}
+		if errs := game.Validate(); errs != nil {
+			t.Errorf("Validate() = %v, want no problems", errs)
+		}
+	})
+
+	t.Run("result disagrees with tag", func(t *testing.T) {
+		game, err := chessnote.ParseString(`[Result "1-0"] 1. e4 e5 0-1`)
+		if err != nil {
+			t.Fatalf("ParseString() failed: %v", err)
+		}
+		errs := game.Validate()
+		if len(errs) != 1 || !strings.Contains(errs[0].Error(), "disagrees with Result tag") {
+			t.Errorf("Validate() = %v, want a single result disagreement", errs)
+		}
+	})
+
+	t.Run("all problems are reported", func(t *testing.T) {
+		game, err := chessnote.ParseString(`1. e4 e5 3. Nf3 Nc6 3. Ke3`, chessnote.WithLaxParsing())
+		if err != nil {
+			t.Fatalf("ParseString() failed: %v", err)
+		}
+		game.Moves[0].NAGs = []int{300}
+		game.Moves[1].Variations = [][]chessnote.Move{{}}
+
+		errs := game.Validate()
+		wantSubstrings := []string{
+			"move number 3 does not match",
+			"NAG $300 is out of range",
+			"variation 1 is empty",
+			"unrecognized result",
+			"illegal mainline",
+		}
+		if len(errs) != len(wantSubstrings) {
+			t.Fatalf("Validate() returned %d problems, want %d: %v", len(errs), len(wantSubstrings), errs)
+		}
+		for i, want := range wantSubstrings {
+			if !strings.Contains(errs[i].Error(), want) {
+				t.Errorf("problem %d = %q, want it to contain %q", i, errs[i], want)
+			}
+		}
+	})
+
+	t.Run("PlyCount tag", func(t *testing.T) {
+		tests := []struct {
+			name string
+			pgn  string
+			want string
+		}{
+			{"matching", "[PlyCount \"3\"]\n1. e4 e5 2. Nf3 *", ""},
+			{"truncated game", "[PlyCount \"41\"]\n1. e4 e5 2. Nf3 *", "PlyCount tag declares 41 plies, but the mainline has 3"},
+			{"invalid value", "[PlyCount \"x\"]\n1. e4 e5 2. Nf3 *", "invalid PlyCount tag"},
+		}
+		for _, tt := range tests {
+			game, err := chessnote.ParseString(tt.pgn)
+			if err != nil {
+				t.Fatalf("ParseString() failed: %v", err)
+			}
+			errs := game.Validate()
+			if tt.want == "" {
+				if errs != nil {
+					t.Errorf("%s: Validate() = %v, want no problems", tt.name, errs)
+				}
+				continue
+			}
+			if len(errs) != 1 || !strings.Contains(errs[0].Error(), tt.want) {
+				t.Errorf("%s: Validate() = %v, want a problem containing %q", tt.name, errs, tt.want)
+			}
+		}
+	})
+
+	t.Run("bogus disambiguation", func(t *testing.T) {
+		tests := []struct {
+			name string
+			pgn  string
+			want string
+		}{
+			{"piece on the wrong file", "1. Nf3 e5 2. Nbd4 *", `ply 3 (Nbd4): disambiguation "b" does not match any knight that can move to d4`},
+			{"piece on the wrong rank", "1. Nf3 e5 2. N4d4 *", `ply 3 (N4d4): disambiguation "4" does not match any knight that can move to d4`},
+			{"pawn capture from the wrong file", "1. e4 d5 2. cxd5 *", `ply 3 (cxd5): disambiguation "c" does not match any pawn that can move to d5`},
+		}
+		for _, tt := range tests {
+			game, err := chessnote.ParseString(tt.pgn)
+			if err != nil {
+				t.Fatalf("%s: ParseString() failed: %v", tt.name, err)
+			}
+			errs := game.Validate()
+			if len(errs) != 1 || !strings.Contains(errs[0].Error(), tt.want) {
+				t.Errorf("%s: Validate() = %v, want a problem containing %q", tt.name, errs, tt.want)
+			}
+		}
+	})
+
+	t.Run("illegal castling", func(t *testing.T) {
+		tests := []struct {
+			name string
+			pgn  string
+			want string
+		}{
+			{"after the king moved", "1. e4 e5 2. Nf3 Nc6 3. Bc4 Bc5 4. Ke2 Nf6 5. Ke1 d6 6. O-O *", "ply 11 (O-O): cannot castle: the right to castle has been forfeited"},
+			{"through check", "1. e4 b6 2. Nf3 Ba6 3. Bb5 Bxb5 4. O-O *", "ply 7 (O-O): cannot castle: f1 is attacked"},
+		}
+		for _, tt := range tests {
+			game, err := chessnote.ParseString(tt.pgn)
+			if err != nil {
+				t.Fatalf("ParseString() failed: %v", err)
+			}
+			errs := game.Validate()
+			if len(errs) != 1 || !strings.Contains(errs[0].Error(), tt.want) {
+				t.Errorf("%s: Validate() = %v, want a problem containing %q", tt.name, errs, tt.want)
+			}
+		}
+	})
+}
+
+func TestParseMoveNumberWarnings(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		pgn   string
+		wantN int
+	}{
+		{"consistent numbers", "1. e4 e5 2. Nf3 1-0", 0},
+		{"black move numbers", "1. e4 1... e5 2. Nf3 2... Nc6 1-0", 0},
+		{"variation numbers", "1. e4 e5 2. Nf3 (2. f4 exf4 3. Nf3) 2... Nc6 1-0", 0},
+		{"skipped number", "1. e4 e5 3. Nf3 1-0", 1},
+		{"wrong number in variation", "1. e4 e5 (2... c5) 2. Nf3 1-0", 1},
+		{"ellipsis from a FEN", "[FEN \"rnbqkb1r/pppppppp/8/8/2PP4/8/PP2PPPP/RNBQKBNR b KQkq - 0 12\"]\n12... Nf6 13. Nc3 1-0", 0},
+		{"ellipsis character", "1. e4 1… e5 2. Nf3 1-0", 0},
+		{"single dot before a Black move", "1. e4 1. e5 2. Nf3 1-0", 0},
+		{"ellipsis before the first move starts with Black", "12... Nf6 13. Nc3 d5 1-0", 0},
+		{"ellipsis before the first move at move 1", "1... e4 e5 2. Nf3 1-0", 0},
+		{"ellipsis before a White move", "1. e4 e5 2... Nf3 1-0", 1},
+		{"ellipsis against a FEN with White to move", "[FEN \"rnbqkbnr/pppppppp/8/8/2PP4/8/PP2PPPP/RNBQKBNR w KQkq - 0 12\"]\n12... Nf6 1-0", 1},
+		{"ellipsis character before a White move", "1. e4 e5 2… Nf3 1-0", 1},
+		{"number too large to check", "1. e4 e5 123456789012345678901234567890. Nf3 1-0", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game, err := chessnote.ParseString(tt.pgn)
+			if err != nil {
+				t.Fatalf("ParseString() failed: %v", err)
+			}
+			if len(game.ParseWarnings) != tt.wantN {
+				t.Errorf("got %d warnings %q, want %d", len(game.ParseWarnings), game.ParseWarnings, tt.wantN)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package chessnote_test
+
+import (
+	"testing"
+
+	"github.com/YashBhalodi/chessnote"
+)
+
+func TestParseVariant(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		tag  string
+		want chessnote.Variant
+	}{
+		{"", chessnote.VariantStandard},
+		{"Standard", chessnote.VariantStandard},
+		{"normal", chessnote.VariantStandard},
+		{"Chess960", chessnote.VariantChess960},
+		{"Fischerandom", chessnote.VariantChess960},
+		{"Crazyhouse", chessnote.VariantCrazyhouse},
+		{"Three-check", chessnote.VariantThreeCheck},
+		{"threeCheck", chessnote.VariantThreeCheck},
+		{"King of the Hill", chessnote.VariantKingOfTheHill},
+		{"kingOfTheHill", chessnote.VariantKingOfTheHill},
+		{"Atomic", chessnote.VariantAtomic},
+		{"Antichess", chessnote.VariantAntichess},
+		{"Horde", chessnote.VariantHorde},
+		{"Racing Kings", chessnote.VariantRacingKings},
+		{"racing_kings", chessnote.VariantRacingKings},
+		{"Bughouse", chessnote.VariantUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			if got := chessnote.ParseVariant(tt.tag); got != tt.want {
+				t.Errorf("ParseVariant(%q) = %v, want %v", tt.tag, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGameVariant(t *testing.T) {
+	t.Parallel()
+	standard, err := chessnote.ParseString("1. e4 e5 2. Ke3 *")
+	if err != nil {
+		t.Fatalf("ParseString() failed: %v", err)
+	}
+	if v := standard.Variant(); v != chessnote.VariantStandard {
+		t.Errorf("Variant() = %v for a game without the tag, want %v", v, chessnote.VariantStandard)
+	}
+	if errs := standard.Validate(); len(errs) != 1 {
+		t.Errorf("Validate() = %v, want the illegal move reported for standard chess", errs)
+	}
+
+	atomic, err := chessnote.ParseString("[Variant \"Atomic\"]\n1. e4 e5 2. Ke3 *")
+	if err != nil {
+		t.Fatalf("ParseString() failed: %v", err)
+	}
+	if v := atomic.Variant(); v != chessnote.VariantAtomic || v.String() != "Atomic" {
+		t.Errorf("Variant() = %v, want %v", v, chessnote.VariantAtomic)
+	}
+	if errs := atomic.Validate(); errs != nil {
+		t.Errorf("Validate() = %v, want no replay for a variant game", errs)
+	}
+
+	const fen = "[Variant \"Chess960\"]\n[SetUp \"1\"]\n[FEN \"bqnbrkrn/pppppppp/8/8/8/8/PPPPPPPP/BQNBRKRN w KQkq - 0 1\"]\n"
+	legal, err := chessnote.ParseString(fen + "1. O-O O-O 2. Nd3 Nd6 *")
+	if err != nil {
+		t.Fatalf("ParseString() failed: %v", err)
+	}
+	if errs := legal.Validate(); errs != nil {
+		t.Errorf("Validate() = %v, want no errors for a legal Chess960 game", errs)
+	}
+	illegal, err := chessnote.ParseString(fen + "1. O-O O-O 2. Ke3 *")
+	if err != nil {
+		t.Fatalf("ParseString() failed: %v", err)
+	}
+	if errs := illegal.Validate(); len(errs) != 1 {
+		t.Errorf("Validate() = %v, want the illegal move reported for Chess960", errs)
+	}
+}
@@ -0,0 +1,46 @@
+package chessnote
+
+import (
+	"strings"
+	"time"
+)
+
+// timestampLayouts lists the date and time formats recognized in move
+// timestamps, both the PGN "YYYY.MM.DD" style and the ISO 8601 style.
+var timestampLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006.01.02 15:04:05",
+	"2006.01.02 15:04",
+	"2006.01.02",
+}
+
+// commentTimestamp extracts a correspondence timestamp from a comment: the
+// value of its first "[%ts ...]" command that holds a recognized date, or
+// else the comment itself if it holds nothing but a date. Times without a
+// zone are taken to be UTC.
+func commentTimestamp(comment string) (time.Time, bool) {
+	for _, cmd := range ParseCommands(comment) {
+		if cmd.Name != "ts" {
+			continue
+		}
+		if ts, ok := parseTimestamp(cmd.Value); ok {
+			return ts, true
+		}
+	}
+	return parseTimestamp(comment)
+}
+
+// parseTimestamp parses s in one of the timestampLayouts.
+func parseTimestamp(s string) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	for _, layout := range timestampLayouts {
+		if ts, err := time.Parse(layout, s); err == nil {
+			return ts, true
+		}
+	}
+	return time.Time{}, false
+}
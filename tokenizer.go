@@ -0,0 +1,77 @@
+package chessnote
+
+import (
+	"io"
+
+	"github.com/YashBhalodi/chessnote/internal/scanner"
+)
+
+// TokenKind identifies the kind of a Token.
+type TokenKind int
+
+// The kinds of token a Tokenizer returns.
+const (
+	TokenIllegal    TokenKind = iota // A character that starts no token
+	TokenEOF                         // The end of the input
+	TokenIdent                       // A tag name, move or result, e.g. "Event", "Nf3", "1-0"
+	TokenNumber                      // A move number, e.g. "12"
+	TokenString                      // A tag value
+	TokenLBracket                    // [
+	TokenRBracket                    // ]
+	TokenLParen                      // (
+	TokenRParen                      // )
+	TokenDot                         // . or the ellipsis character …
+	TokenAsterisk                    // *
+	TokenComment                     // A brace or line comment
+	TokenNAG                         // A Numeric Annotation Glyph, e.g. $1
+	TokenGlyph                       // An inline annotation glyph, e.g. !, ??, !?
+	TokenWhitespace                  // A run of whitespace; see Tokenizer.SetPreserveWhitespace
+)
+
+// tokenKinds maps the scanner's token types to their TokenKind.
+var tokenKinds = [...]TokenKind{
+	scanner.ILLEGAL:    TokenIllegal,
+	scanner.EOF:        TokenEOF,
+	scanner.IDENT:      TokenIdent,
+	scanner.NUMBER:     TokenNumber,
+	scanner.STRING:     TokenString,
+	scanner.LBRACKET:   TokenLBracket,
+	scanner.RBRACKET:   TokenRBracket,
+	scanner.LPAREN:     TokenLParen,
+	scanner.RPAREN:     TokenRParen,
+	scanner.DOT:        TokenDot,
+	scanner.ASTERISK:   TokenAsterisk,
+	scanner.COMMENT:    TokenComment,
+	scanner.NAG:        TokenNAG,
+	scanner.GLYPH:      TokenGlyph,
+	scanner.WHITESPACE: TokenWhitespace,
+}
+
+// Tokenizer splits PGN text into the tokens the parser reads, for tools
+// such as formatters and linters that work on the source rather than on
+// parsed games. A Tokenizer is not safe for concurrent use by multiple
+// goroutines.
+type Tokenizer struct {
+	s *scanner.Scanner
+}
+
+// NewTokenizer returns a Tokenizer that reads from r.
+func NewTokenizer(r io.Reader) *Tokenizer {
+	return &Tokenizer{s: scanner.NewScanner(r)}
+}
+
+// SetPreserveWhitespace controls whether Next returns the whitespace
+// between tokens as TokenWhitespace tokens holding the exact run of
+// characters, so that a formatter can keep or normalize it. By default,
+// whitespace is skipped.
+func (t *Tokenizer) SetPreserveWhitespace(preserve bool) {
+	t.s.SetPreserveWhitespace(preserve)
+}
+
+// Next returns the next token of the input. At the end of the input it
+// returns a token of kind TokenEOF, and keeps doing so on later calls.
+func (t *Tokenizer) Next() Token {
+	tok := t.s.Scan()
+	pos := t.s.Pos()
+	return Token{Kind: tokenKinds[tok.Type], Literal: tok.Literal, Line: pos.Line, Column: pos.Column}
+}
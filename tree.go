@@ -0,0 +1,96 @@
+package chessnote
+
+// MoveRef identifies a move within a game's tree of moves and variations.
+type MoveRef struct {
+	// Path locates the move in the tree. It alternates move indices and
+	// variation indices: [i] is g.Moves[i], and [i, v, j] is move j of
+	// variation v of g.Moves[i]. Deeper variations extend the pattern, so a
+	// path always has an odd length.
+	Path []int
+	// Ply is the 1-based half-move number of the move, counted from the
+	// start of the game. A variation's first move has the same ply as the
+	// move it replaces.
+	Ply int
+	// Move is a copy of the referenced move.
+	Move Move
+}
+
+// FindMoves returns a reference to every move in the game, including moves
+// inside variations, for which pred returns true. Moves are visited in
+// source order: each move is followed by its variations before the next
+// move of the same line.
+func (g *Game) FindMoves(pred func(Move) bool) []MoveRef {
+	var refs []MoveRef
+	walkMoves(g.Moves, nil, 1, func(ref MoveRef) {
+		if pred(ref.Move) {
+			refs = append(refs, ref)
+		}
+	})
+	return refs
+}
+
+// MoveAt returns the move at path, which follows the MoveRef.Path
+// convention: it alternates move indices and variation indices, so [i] is
+// g.Moves[i] and [i, v, j] is move j of variation v of g.Moves[i]. The
+// returned pointer refers to the move inside the game, so changes to it are
+// visible in g. MoveAt returns false if path is empty, has an even length,
+// or any of its indices is out of range.
+func (g *Game) MoveAt(path []int) (*Move, bool) {
+	if len(path)%2 == 0 {
+		return nil, false
+	}
+	line := g.Moves
+	for k := 0; ; k += 2 {
+		i := path[k]
+		if i < 0 || i >= len(line) {
+			return nil, false
+		}
+		m := &line[i]
+		if k+1 == len(path) {
+			return m, true
+		}
+		v := path[k+1]
+		if v < 0 || v >= len(m.Variations) {
+			return nil, false
+		}
+		line = m.Variations[v]
+	}
+}
+
+// walkMoves calls fn for every move of a line and, recursively, its
+// variations. prefix is the path of the line within the game and firstPly
+// the ply of its first move.
+func walkMoves(moves []Move, prefix []int, firstPly int, fn func(MoveRef)) {
+	for i, m := range moves {
+		path := make([]int, len(prefix)+1)
+		copy(path, prefix)
+		path[len(prefix)] = i
+
+		fn(MoveRef{Path: path, Ply: firstPly + i, Move: m})
+		for v, variation := range m.Variations {
+			walkMoves(variation, append(path[:len(path):len(path)], v), firstPly+i, fn)
+		}
+	}
+}
+
+// replayMoves is like walkMoves, but also passes fn the positions before and
+// after each move. b holds the position before the line's first move and is advanced
+// along the line. A line is abandoned at its first illegal move, along with
+// any variations of that move.
+func replayMoves(b *Board, moves []Move, prefix []int, firstPly int, fn func(ref MoveRef, before, after *Board)) {
+	for i, m := range moves {
+		path := make([]int, len(prefix)+1)
+		copy(path, prefix)
+		path[len(prefix)] = i
+
+		before := *b
+		if err := b.Apply(m); err != nil {
+			return
+		}
+		fn(MoveRef{Path: path, Ply: firstPly + i, Move: m}, &before, b)
+		for v, variation := range m.Variations {
+			start := before
+			replayMoves(&start, variation, append(path[:len(path):len(path)], v), firstPly+i, fn)
+		}
+	}
+}
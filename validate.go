@@ -0,0 +1,73 @@
+package chessnote
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Validate checks the game's structural invariants and returns every problem
+// it finds, rather than stopping at the first one, so a whole database can
+// be audited in one pass. It returns nil for a game with no problems.
+//
+// The checks are: every variation contains at least one move, every NAG is
+// in the range 0-255, the result is present, recognized and consistent with
+// the Result tag, the PlyCount tag, if present, is a valid count matching
+// the number of mainline moves, the mainline can be legally replayed from the game's
+// initial position, and any problems recorded in ParseWarnings, such as
+// inconsistent move numbers. The replay follows the rules of standard chess,
+// which also cover Chess960 once the starting position is set up from the
+// FEN tag, so it is skipped for games of other variants (see Game.Variant).
+func (g *Game) Validate() []error {
+	var errs []error
+	for _, w := range g.ParseWarnings {
+		errs = append(errs, errors.New(w))
+	}
+	validateLine(g.Moves, 1, &errs)
+	if g.Outcome() == OutcomeUnknown {
+		errs = append(errs, fmt.Errorf("missing or unrecognized result %q", g.resultString()))
+	} else if !g.ResultConsistent() {
+		errs = append(errs, fmt.Errorf("result %q disagrees with Result tag %q", g.Result, g.Tags["Result"]))
+	}
+	if value, ok := g.Tags["PlyCount"]; ok {
+		if n, ok := g.DeclaredPlyCount(); !ok {
+			errs = append(errs, fmt.Errorf("invalid PlyCount tag %q", value))
+		} else if n != len(g.Moves) {
+			errs = append(errs, fmt.Errorf("PlyCount tag declares %d plies, but the mainline has %d", n, len(g.Moves)))
+		}
+	}
+	if v := g.Variant(); v == VariantStandard || v == VariantChess960 {
+		if _, err := g.Positions(); err != nil {
+			errs = append(errs, fmt.Errorf("illegal mainline: %w", err))
+		}
+	}
+	return errs
+}
+
+// validateLine checks the moves of a single line, whose first move is played
+// at firstPly, and its variations recursively.
+func validateLine(moves []Move, firstPly int, errs *[]error) {
+	for i, m := range moves {
+		ply := firstPly + i
+		for _, nag := range m.NAGs {
+			if nag < 0 || nag > 255 {
+				*errs = append(*errs, fmt.Errorf("ply %d: NAG $%d is out of range 0-255", ply, nag))
+			}
+		}
+		for j, variation := range m.Variations {
+			if len(variation) == 0 {
+				*errs = append(*errs, fmt.Errorf("ply %d: variation %d is empty", ply, j+1))
+				continue
+			}
+			validateLine(variation, ply, errs)
+		}
+	}
+}
+
+// resultString returns the game's result token, falling back to its Result
+// tag.
+func (g *Game) resultString() string {
+	if g.Result != "" {
+		return g.Result
+	}
+	return g.Tags["Result"]
+}
@@ -0,0 +1,109 @@
+package chessnote
+
+import "strings"
+
+// Variant is the chess variant a game is played in, as named by its
+// Variant tag.
+type Variant int
+
+const (
+	// VariantStandard is standard chess. It is the zero value for Variant
+	// and the variant of games without a Variant tag.
+	VariantStandard Variant = iota
+	// VariantUnknown is a variant this package does not recognize.
+	VariantUnknown
+	// VariantChess960 is Fischer random chess.
+	VariantChess960
+	// VariantCrazyhouse lets captured pieces be dropped back on the board.
+	VariantCrazyhouse
+	// VariantThreeCheck is won by giving check three times.
+	VariantThreeCheck
+	// VariantKingOfTheHill is won by bringing the king to the center.
+	VariantKingOfTheHill
+	// VariantAtomic makes captures explode the surrounding pieces.
+	VariantAtomic
+	// VariantAntichess makes captures compulsory; losing all pieces wins.
+	VariantAntichess
+	// VariantHorde pits White's pawn horde against Black's normal army.
+	VariantHorde
+	// VariantRacingKings is won by racing the king to the eighth rank.
+	VariantRacingKings
+)
+
+// variantNames maps the spellings of each variant found in Variant tags,
+// lowercased and without spaces, hyphens or underscores, to the variant.
+var variantNames = map[string]Variant{
+	"standard":      VariantStandard,
+	"normal":        VariantStandard,
+	"chess":         VariantStandard,
+	"chess960":      VariantChess960,
+	"960":           VariantChess960,
+	"fischerandom":  VariantChess960,
+	"fischerrandom": VariantChess960,
+	"crazyhouse":    VariantCrazyhouse,
+	"threecheck":    VariantThreeCheck,
+	"3check":        VariantThreeCheck,
+	"kingofthehill": VariantKingOfTheHill,
+	"koth":          VariantKingOfTheHill,
+	"atomic":        VariantAtomic,
+	"antichess":     VariantAntichess,
+	"giveaway":      VariantAntichess,
+	"horde":         VariantHorde,
+	"racingkings":   VariantRacingKings,
+}
+
+// String returns the usual name of the variant, such as "Chess960", or
+// "Unknown" for VariantUnknown.
+func (v Variant) String() string {
+	switch v {
+	case VariantStandard:
+		return "Standard"
+	case VariantChess960:
+		return "Chess960"
+	case VariantCrazyhouse:
+		return "Crazyhouse"
+	case VariantThreeCheck:
+		return "Three-check"
+	case VariantKingOfTheHill:
+		return "King of the Hill"
+	case VariantAtomic:
+		return "Atomic"
+	case VariantAntichess:
+		return "Antichess"
+	case VariantHorde:
+		return "Horde"
+	case VariantRacingKings:
+		return "Racing Kings"
+	default:
+		return "Unknown"
+	}
+}
+
+// ParseVariant converts the value of a Variant tag into a Variant. Case,
+// spaces, hyphens and underscores are ignored, so "Three-check",
+// "threeCheck" and "three_check" all map to VariantThreeCheck. An empty
+// value is VariantStandard, and an unrecognized one VariantUnknown.
+func ParseVariant(s string) Variant {
+	key := strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '_', '\t':
+			return -1
+		}
+		return r
+	}, strings.ToLower(s))
+	if key == "" {
+		return VariantStandard
+	}
+	if v, ok := variantNames[key]; ok {
+		return v
+	}
+	return VariantUnknown
+}
+
+// Variant returns the variant the game is played in, from its Variant tag,
+// as interpreted by ParseVariant. Games without the tag are standard chess.
+// The board replay implements the rules of standard chess and Chess960 only,
+// so tools can use it to skip games they cannot handle.
+func (g *Game) Variant() Variant {
+	return ParseVariant(g.Tags["Variant"])
+}